@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+)
+
+/// ---------- FEED STRUCTS ---------- ///
+
+// FeedItem is a feed entry normalised from either RSS 2.0 or Atom
+type FeedItem struct {
+	Title      string
+	Link       string
+	Summary    string
+	Body       string
+	Author     string
+	Published  time.Time
+	Categories []string
+	SourceName string
+}
+
+type rssFeed struct {
+	XMLName xml.Name `xml:"rss"`
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Author      string   `xml:"author"`
+	Creator     string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	PubDate     string   `xml:"pubDate"`
+	Date        string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Categories  []string `xml:"category"`
+	GUID        string   `xml:"guid"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title     string `xml:"title"`
+	Summary   string `xml:"summary"`
+	Content   string `xml:"content"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+	Links     []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+	Authors []struct {
+		Name string `xml:"name"`
+	} `xml:"author"`
+	Categories []struct {
+		Term  string `xml:"term,attr"`
+		Label string `xml:"label,attr"`
+	} `xml:"category"`
+}
+
+/// ---------- PARSING ---------- ///
+
+var errUnknownFeed = errors.New("document is neither an RSS 2.0 nor an Atom feed")
+
+// ParseFeed detects the feed format from the root element and returns its items
+func ParseFeed(data []byte) ([]FeedItem, error) {
+	root, err := rootElement(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case root.Local == "rss":
+		return parseRSS(data)
+	case root.Local == "feed" && root.Space == "http://www.w3.org/2005/Atom":
+		return parseAtom(data)
+	}
+	return nil, errUnknownFeed
+}
+
+func rootElement(data []byte) (xml.Name, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return xml.Name{}, fmt.Errorf("reading feed: %w", err)
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start.Name, nil
+		}
+	}
+}
+
+func parseRSS(data []byte) ([]FeedItem, error) {
+	var feed rssFeed
+	if err := unmarshalFeed(data, &feed); err != nil {
+		return nil, err
+	}
+
+	items := make([]FeedItem, 0, len(feed.Channel.Items))
+	for _, it := range feed.Channel.Items {
+		link := strings.TrimSpace(it.Link)
+		if link == "" && strings.HasPrefix(strings.TrimSpace(it.GUID), "http") {
+			link = strings.TrimSpace(it.GUID)
+		}
+
+		author := firstNonEmpty(it.Creator, it.Author)
+		published, _ := parseFeedDate(firstNonEmpty(it.PubDate, it.Date))
+
+		items = append(items, FeedItem{
+			Title:      cleanText(it.Title),
+			Link:       link,
+			Summary:    cleanText(it.Description),
+			Body:       strings.TrimSpace(firstNonEmpty(it.Content, it.Description)),
+			Author:     strings.TrimSpace(author),
+			Published:  published,
+			Categories: it.Categories,
+			SourceName: cleanText(feed.Channel.Title),
+		})
+	}
+	return items, nil
+}
+
+func parseAtom(data []byte) ([]FeedItem, error) {
+	var feed atomFeed
+	if err := unmarshalFeed(data, &feed); err != nil {
+		return nil, err
+	}
+
+	items := make([]FeedItem, 0, len(feed.Entries))
+	for _, e := range feed.Entries {
+		// Prefer the alternate link, falling back to the first one
+		var link string
+		for _, l := range e.Links {
+			if l.Rel == "" || l.Rel == "alternate" {
+				link = l.Href
+				break
+			}
+		}
+		if link == "" && len(e.Links) > 0 {
+			link = e.Links[0].Href
+		}
+
+		var authors []string
+		for _, a := range e.Authors {
+			if name := strings.TrimSpace(a.Name); name != "" {
+				authors = append(authors, name)
+			}
+		}
+
+		var categories []string
+		for _, cat := range e.Categories {
+			categories = append(categories, firstNonEmpty(cat.Term, cat.Label))
+		}
+
+		published, _ := parseFeedDate(firstNonEmpty(e.Published, e.Updated))
+
+		items = append(items, FeedItem{
+			Title:      cleanText(e.Title),
+			Link:       strings.TrimSpace(link),
+			Summary:    cleanText(firstNonEmpty(e.Summary, e.Content)),
+			Body:       strings.TrimSpace(firstNonEmpty(e.Content, e.Summary)),
+			Author:     strings.Join(authors, ", "),
+			Published:  published,
+			Categories: categories,
+			SourceName: cleanText(feed.Title),
+		})
+	}
+	return items, nil
+}
+
+func unmarshalFeed(data []byte, v interface{}) error {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+	dec.Entity = xml.HTMLEntity
+	return dec.Decode(v)
+}
+
+/// ---------- HELPER FUNCTIONS ---------- ///
+
+// Date layouts seen in the wild for pubDate, dc:date, published and updated
+var feedDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 MST",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+func parseFeedDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range feedDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised date %q", s)
+}
+
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+var whitespacePattern = regexp.MustCompile(`[\s\p{Zs}]+`)
+
+// cleanText strips markup and collapses whitespace for plain-text fields
+func cleanText(s string) string {
+	s = htmlTagPattern.ReplaceAllString(s, " ")
+	s = html.UnescapeString(s)
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(s, " "))
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
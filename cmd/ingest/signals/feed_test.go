@@ -0,0 +1,152 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func parseFixture(t *testing.T, name string) []FeedItem {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err := ParseFeed(data)
+	if err != nil {
+		t.Fatalf("ParseFeed(%s): %v", name, err)
+	}
+	return items
+}
+
+func TestParseRSS(t *testing.T) {
+	items := parseFixture(t, "rss2.xml")
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+
+	first := items[0]
+	if first.Title != "Salmon prices climb as Norway exports slow" {
+		t.Errorf("Title = %q", first.Title)
+	}
+	if first.Link != "https://news.example.com/salmon-prices" {
+		t.Errorf("Link = %q", first.Link)
+	}
+	if first.Summary != "Atlantic salmon prices rose 4% this week." {
+		t.Errorf("Summary = %q", first.Summary)
+	}
+	if first.Body != "<p>Atlantic salmon prices rose 4% this week as exports from Norway slowed.</p>" {
+		t.Errorf("Body = %q", first.Body)
+	}
+	if first.Author != "Kari Nordmann" {
+		t.Errorf("Author = %q, want dc:creator over author", first.Author)
+	}
+	if want := time.Date(2025, 10, 14, 8, 30, 0, 0, time.UTC); !first.Published.Equal(want) {
+		t.Errorf("Published = %v, want %v", first.Published, want)
+	}
+	if want := []string{"Salmon", " Prices ", "salmon"}; !reflect.DeepEqual(first.Categories, want) {
+		t.Errorf("Categories = %q, want %q", first.Categories, want)
+	}
+	if first.SourceName != "Seafood & Fisheries Daily" {
+		t.Errorf("SourceName = %q", first.SourceName)
+	}
+
+	// guid stands in for a missing link, and dc:date for a missing pubDate
+	second := items[1]
+	if second.Link != "https://news.example.com/shrimp-quota" {
+		t.Errorf("Link = %q, want the guid", second.Link)
+	}
+	if second.Body != "Regulators cut the northern shrimp quota." {
+		t.Errorf("Body = %q, want the description", second.Body)
+	}
+	if want := time.Date(2025, 10, 13, 17, 0, 0, 0, time.UTC); !second.Published.Equal(want) {
+		t.Errorf("Published = %v, want %v", second.Published, want)
+	}
+}
+
+func TestParseAtom(t *testing.T) {
+	items := parseFixture(t, "atom.xml")
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+
+	first := items[0]
+	if first.Link != "https://watch.example.org/cod-landings" {
+		t.Errorf("Link = %q, want the alternate link", first.Link)
+	}
+	if first.Summary != "Cod landings fell 12% year on year." {
+		t.Errorf("Summary = %q", first.Summary)
+	}
+	if first.Author != "Ola Nordmann, Jane Doe" {
+		t.Errorf("Author = %q", first.Author)
+	}
+	if want := time.Date(2025, 10, 12, 4, 0, 0, 0, time.UTC); !first.Published.Equal(want) {
+		t.Errorf("Published = %v, want published converted to UTC %v", first.Published, want)
+	}
+	if first.Published.Location() != time.UTC {
+		t.Errorf("Published location = %v, want UTC", first.Published.Location())
+	}
+	if want := []string{"cod", "Landings"}; !reflect.DeepEqual(first.Categories, want) {
+		t.Errorf("Categories = %q, want %q", first.Categories, want)
+	}
+	if first.SourceName != "Fish Market Watch" {
+		t.Errorf("SourceName = %q", first.SourceName)
+	}
+
+	// A link without rel is the alternate link; updated stands in for published
+	second := items[1]
+	if second.Link != "https://watch.example.org/tuna-auction" {
+		t.Errorf("Link = %q", second.Link)
+	}
+	if second.Summary != "First tuna auction of the season." {
+		t.Errorf("Summary = %q, want the content", second.Summary)
+	}
+	if want := time.Date(2025, 10, 11, 0, 0, 0, 0, time.UTC); !second.Published.Equal(want) {
+		t.Errorf("Published = %v, want %v", second.Published, want)
+	}
+}
+
+func TestParseFeedDates(t *testing.T) {
+	items := parseFixture(t, "malformed_dates.xml")
+
+	want := map[string]time.Time{
+		"Single digit day": time.Date(2025, 10, 6, 7, 5, 0, 0, time.UTC),
+		"No weekday":       time.Date(2025, 10, 6, 6, 5, 0, 0, time.UTC),
+		"Date only":        time.Date(2025, 10, 6, 0, 0, 0, 0, time.UTC),
+		"Padded":           time.Date(2025, 10, 6, 7, 5, 0, 0, time.UTC),
+		"Not a date":       {},
+		"US style":         {},
+		"No date":          {},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("got %d items, want %d", len(items), len(want))
+	}
+	for _, item := range items {
+		expected, ok := want[item.Title]
+		if !ok {
+			t.Errorf("unexpected item %q", item.Title)
+			continue
+		}
+		if !item.Published.Equal(expected) {
+			t.Errorf("%s: Published = %v, want %v", item.Title, item.Published, expected)
+		}
+	}
+}
+
+func TestParseFeedRejects(t *testing.T) {
+	tests := map[string]string{
+		"empty":      "",
+		"not a feed": `<html><body>hello</body></html>`,
+		"feed no ns": `<feed><entry><title>x</title></entry></feed>`,
+		"not xml":    `{"items": []}`,
+	}
+	for name, doc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseFeed([]byte(doc)); err == nil {
+				t.Error("ParseFeed succeeded, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/config"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/database"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Usage:
+//
+//	go run ./cmd/ingest/signals [-list feeds.txt] [-link] [feed ...]
+//
+// Each feed is a local file path or an http(s) URL. The list file holds one
+// feed per line; blank lines and lines starting with # are ignored.
+func main() {
+	listFile := flag.String("list", "", "file with one feed path or URL per line")
+	link := flag.Bool("link", false, "link signals to species, regions and landing names named in their title or summary")
+	flag.Parse()
+
+	sources := flag.Args()
+	if *listFile != "" {
+		listed, err := readSourceList(*listFile)
+		if err != nil {
+			log.Fatalf("❌ Failed to read feed list: %v", err)
+		}
+		sources = append(sources, listed...)
+	}
+	if len(sources) == 0 {
+		log.Fatal("❌ No feeds given. Pass feed paths/URLs or -list <file>.")
+	}
+
+	config.LoadEnv()
+	db := database.SetupDB()
+
+	var matcher *entityMatcher
+	if *link {
+		var err error
+		if matcher, err = loadEntityMatcher(db); err != nil {
+			log.Fatalf("❌ Failed to load species and regions: %v", err)
+		}
+	}
+
+	total := 0
+	for _, source := range sources {
+		data, err := readSource(source)
+		if err != nil {
+			log.Printf("❌ Failed to read %s: %v", source, err)
+			continue
+		}
+
+		items, err := ParseFeed(data)
+		if err != nil {
+			log.Printf("❌ Failed to parse %s: %v", source, err)
+			continue
+		}
+
+		n := 0
+		for _, item := range items {
+			if err := upsertSignal(db, item, matcher); err != nil {
+				log.Printf("⚠️ Skipping %q from %s: %v", item.Title, source, err)
+				continue
+			}
+			n++
+		}
+		log.Printf("✅ %s: %d of %d entries ingested", source, n, len(items))
+		total += n
+	}
+
+	fmt.Printf("✅ Ingested %d market signals from %d feeds\n", total, len(sources))
+}
+
+/// ---------- SOURCES ---------- ///
+
+func readSourceList(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var sources []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		sources = append(sources, line)
+	}
+	return sources, scanner.Err()
+}
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// maxFeedBytes caps how much of a remote feed is read
+const maxFeedBytes = 10 << 20
+
+func readSource(source string) ([]byte, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		resp, err := httpClient.Get(source)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedBytes+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > maxFeedBytes {
+			return nil, fmt.Errorf("feed is larger than %d bytes", maxFeedBytes)
+		}
+		return data, nil
+	}
+	return os.ReadFile(source)
+}
+
+/// ---------- UPSERT ---------- ///
+
+// upsertSignal inserts or refreshes a signal keyed on the idx_title_published unique index,
+// so ingesting the same feed twice leaves a single row per entry
+func upsertSignal(db *gorm.DB, item FeedItem, matcher *entityMatcher) error {
+	signal, err := signalFromItem(item)
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := saveSignal(tx, &signal); err != nil {
+			return err
+		}
+
+		if err := syncSignalTags(tx, signal.ID, signalTags(item)); err != nil {
+			return err
+		}
+
+		if matcher != nil {
+			return matcher.link(tx, signal.ID, item.Title+" "+item.Summary)
+		}
+		return nil
+	})
+}
+
+// syncSignalTags makes tags the signal's full tag set, dropping any the feed no longer lists
+func syncSignalTags(tx *gorm.DB, signalID uint, tags []string) error {
+	stale := tx.Where("market_signal_id = ?", signalID)
+	if len(tags) > 0 {
+		stale = stale.Where("tag NOT IN ?", tags)
+	}
+	if err := stale.Delete(&models.MarketSignalTag{}).Error; err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.MarketSignalTag{MarketSignalID: signalID, Tag: tag}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// signalFromItem maps a feed entry onto a signal row, truncated to the column sizes
+func signalFromItem(item FeedItem) (models.MarketSignal, error) {
+	if item.Title == "" {
+		return models.MarketSignal{}, fmt.Errorf("missing title")
+	}
+	if item.Published.IsZero() {
+		return models.MarketSignal{}, fmt.Errorf("missing or unparseable publish date")
+	}
+
+	return models.MarketSignal{
+		Title:         truncate(item.Title, 255),
+		PublishedDate: item.Published,
+		Author:        truncate(item.Author, 255),
+		Summary:       item.Summary,
+		Body:          item.Body,
+		SourceURL:     truncate(item.Link, 1024),
+		SourceName:    truncate(item.SourceName, 255),
+	}, nil
+}
+
+// signalUpsert refreshes an existing row on re-ingest. New rows have no
+// deleted_at, so taking the excluded value restores a deleted signal.
+var signalUpsert = clause.OnConflict{
+	Columns:   []clause.Column{{Name: "title"}, {Name: "published_date"}},
+	DoUpdates: clause.AssignmentColumns([]string{"author", "summary", "body", "source_url", "source_name", "updated_at", "deleted_at"}),
+}
+
+func saveSignal(tx *gorm.DB, signal *models.MarketSignal) error {
+	return tx.Omit(clause.Associations).Clauses(signalUpsert).Create(signal).Error
+}
+
+// signalTags normalises the entry's categories into distinct, non-empty tags
+func signalTags(item FeedItem) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, category := range item.Categories {
+		tag := truncate(models.NormalizeTag(category), 100)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max])
+}
+
+/// ---------- LINKING ---------- ///
+
+type namedEntity struct {
+	ID      uint
+	Name    string
+	Alias   string
+	pattern *regexp.Regexp
+}
+
+// entityMatcher finds species, regions and landing names mentioned by name in signal text
+type entityMatcher struct {
+	species      []namedEntity
+	regions      []namedEntity
+	landingNames []namedEntity
+}
+
+func loadEntityMatcher(db *gorm.DB) (*entityMatcher, error) {
+	m := &entityMatcher{}
+	if err := db.Raw(`SELECT id, name FROM species WHERE deleted_at IS NULL`).Scan(&m.species).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Raw(`SELECT id, region AS name FROM regions WHERE deleted_at IS NULL`).Scan(&m.regions).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Raw(`SELECT id, nmfs_name AS name, COALESCE(scientific_name, '') AS alias FROM landing_names WHERE deleted_at IS NULL`).Scan(&m.landingNames).Error; err != nil {
+		return nil, err
+	}
+	for _, entities := range [][]namedEntity{m.species, m.regions} {
+		for i := range entities {
+			entities[i].pattern = namePattern(entities[i].Name)
+		}
+	}
+	for i := range m.landingNames {
+		m.landingNames[i].pattern = namePattern(landingNameForms(m.landingNames[i].Name, m.landingNames[i].Alias)...)
+	}
+	return m, nil
+}
+
+// namePattern matches any of the names as whole words, ignoring case. It returns
+// nil when every name is blank.
+func namePattern(names ...string) *regexp.Regexp {
+	var alternatives []string
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			alternatives = append(alternatives, regexp.QuoteMeta(name))
+		}
+	}
+	if len(alternatives) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`(?i)\b(?:` + strings.Join(alternatives, "|") + `)\b`)
+}
+
+// landingNameForms returns the ways a landing name shows up in prose. NMFS names
+// are written "SALMON, ATLANTIC", so the reading order "ATLANTIC SALMON" is
+// matched too, along with the scientific name.
+func landingNameForms(nmfsName, scientificName string) []string {
+	forms := []string{nmfsName, scientificName}
+	if parts := strings.Split(nmfsName, ","); len(parts) == 2 {
+		head, qualifier := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if head != "" && qualifier != "" {
+			forms = append(forms, qualifier+" "+head)
+		}
+	}
+	return forms
+}
+
+func (m *entityMatcher) link(tx *gorm.DB, signalID uint, text string) error {
+	links := []struct {
+		table    string
+		column   string
+		entities []namedEntity
+	}{
+		{"market_signal_species", "species_id", m.species},
+		{"market_signal_regions", "region_id", m.regions},
+		{"market_signal_landing_names", "landing_name_id", m.landingNames},
+	}
+
+	for _, l := range links {
+		for _, e := range l.entities {
+			if e.pattern == nil || !e.pattern.MatchString(text) {
+				continue
+			}
+			stmt := fmt.Sprintf(`INSERT INTO %s (market_signal_id, %s) VALUES ($1, $2) ON CONFLICT DO NOTHING`, l.table, l.column)
+			if err := tx.Exec(stmt, signalID, e.ID).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
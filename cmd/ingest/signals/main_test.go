@@ -0,0 +1,150 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestSignalFromItem(t *testing.T) {
+	published := time.Date(2025, 10, 14, 8, 30, 0, 0, time.UTC)
+	item := FeedItem{
+		Title:      strings.Repeat("t", 300),
+		Link:       "https://news.example.com/" + strings.Repeat("p", 2000),
+		Summary:    "summary",
+		Body:       "<p>body</p>",
+		Author:     "Kari Nordmann",
+		Published:  published,
+		SourceName: "Seafood Daily",
+	}
+
+	signal, err := signalFromItem(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signal.Title) != 255 || len(signal.SourceURL) != 1024 {
+		t.Errorf("title/url lengths = %d/%d, want truncated to 255/1024", len(signal.Title), len(signal.SourceURL))
+	}
+	if !signal.PublishedDate.Equal(published) || signal.Author != "Kari Nordmann" ||
+		signal.Summary != "summary" || signal.Body != "<p>body</p>" || signal.SourceName != "Seafood Daily" {
+		t.Errorf("unexpected signal %+v", signal)
+	}
+
+	if _, err := signalFromItem(FeedItem{Published: published}); err == nil {
+		t.Error("accepted an item without a title")
+	}
+	if _, err := signalFromItem(FeedItem{Title: "no date"}); err == nil {
+		t.Error("accepted an item without a publish date")
+	}
+}
+
+func TestSignalTags(t *testing.T) {
+	item := FeedItem{Categories: []string{"Salmon", " Prices ", "salmon", "", "  ", strings.Repeat("x", 150)}}
+	want := []string{"salmon", "prices", strings.Repeat("x", 100)}
+	if got := signalTags(item); !reflect.DeepEqual(got, want) {
+		t.Errorf("signalTags = %q, want %q", got, want)
+	}
+}
+
+// The upsert must refresh the row and clear deleted_at so a re-ingested signal comes back
+func TestSaveSignalSQL(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sql string
+	db.Callback().Create().After("gorm:create").Register("test:capture_sql", func(tx *gorm.DB) {
+		sql = tx.Statement.SQL.String()
+	})
+
+	signal := models.MarketSignal{Title: "Salmon prices climb", PublishedDate: time.Now()}
+	if err := saveSignal(db, &signal); err != nil {
+		t.Fatal(err)
+	}
+	if signal.DeletedAt != nil {
+		t.Fatal("new signal has deleted_at set")
+	}
+
+	for _, want := range []string{
+		`ON CONFLICT ("title","published_date") DO UPDATE SET`,
+		`"summary"="excluded"."summary"`,
+		`"updated_at"="excluded"."updated_at"`,
+		`"deleted_at"="excluded"."deleted_at"`,
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("upsert SQL is missing %s:\n%s", want, sql)
+		}
+	}
+}
+
+// Re-ingesting a signal must drop the tags its feed entry no longer carries
+func TestSyncSignalTagsSQL(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var deletes []string
+	db.Callback().Delete().After("gorm:delete").Register("test:capture_sql", func(tx *gorm.DB) {
+		deletes = append(deletes, tx.Statement.SQL.String())
+	})
+
+	tests := []struct {
+		name string
+		tags []string
+		want string
+	}{
+		{"keeps listed tags", []string{"salmon", "prices"}, `WHERE market_signal_id = $1 AND tag NOT IN ($2,$3)`},
+		{"no tags clears all", nil, `WHERE market_signal_id = $1`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deletes = nil
+			if err := syncSignalTags(db, 7, tt.tags); err != nil {
+				t.Fatal(err)
+			}
+			if len(deletes) != 1 {
+				t.Fatalf("got %d deletes, want 1", len(deletes))
+			}
+			if !strings.HasSuffix(deletes[0], tt.want) {
+				t.Errorf("delete SQL = %s, want it to end with %s", deletes[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestLandingNamePattern(t *testing.T) {
+	pattern := namePattern(landingNameForms("SALMON, ATLANTIC", "Salmo salar")...)
+
+	tests := map[string]bool{
+		"Atlantic salmon prices climb":        true,
+		"Landings of SALMON, ATLANTIC rose":   true,
+		"Salmo salar farms expand in Chile":   true,
+		"Pacific salmon run is late":          false,
+		"Atlantic salmonella outbreak closed": false,
+	}
+	for text, want := range tests {
+		if got := pattern.MatchString(text); got != want {
+			t.Errorf("match %q = %v, want %v", text, got, want)
+		}
+	}
+
+	if namePattern("", "  ") != nil {
+		t.Error("blank names should not produce a pattern")
+	}
+}
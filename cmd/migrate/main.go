@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/manjunath-tintbytes/seafoodai.api/internal/config"
 	"github.com/manjunath-tintbytes/seafoodai.api/internal/database"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
 
 	"log"
 	"os"
@@ -30,8 +31,27 @@ func main() {
 			if err := database.RollbackToMigration(db, os.Args[2]); err != nil {
 				log.Fatal("Rollback failed:", err)
 			}
+		case "set-role":
+			// Bootstraps the first admin, who can then manage roles over the API
+			if len(os.Args) < 4 || !models.ValidRole(os.Args[3]) {
+				log.Fatal("Usage: set-role <email> <viewer|analyst|admin>")
+			}
+			if err := database.UpdateUserRoleByEmail(os.Args[2], os.Args[3]); err != nil {
+				log.Fatal("Set role failed:", err)
+			}
+			entry := &models.AuditLogEntry{
+				Action:     models.AuditUserRoleUpdate,
+				TargetType: "user",
+				TargetID:   os.Args[2],
+				Outcome:    models.AuditSuccess,
+				Details:    "role=" + os.Args[3] + " via migrate set-role",
+			}
+			if err := database.WriteAuditLog(entry); err != nil {
+				log.Println("Audit log write failed:", err)
+			}
+			log.Printf("Role of %s set to %s", os.Args[2], os.Args[3])
 		default:
-			log.Println("Unknown command. Use: migrate, rollback, or rollback-to <migration_id>")
+			log.Println("Unknown command. Use: migrate, rollback, rollback-to <migration_id>, or set-role <email> <role>")
 		}
 	} else {
 		// Your application logic here
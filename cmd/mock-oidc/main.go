@@ -0,0 +1,238 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Usage:
+//
+//	go run ./cmd/mock-oidc [-addr :9000] [-issuer http://localhost:9000] [-email dev@example.com]
+//
+// A throwaway OpenID Connect provider for local development. It approves every
+// authorization request without a login page, signing in as -email (or the
+// login_hint parameter), and enforces PKCE and redirect URI checks on the token
+// endpoint. Point an OIDC_<NAME>_ISSUER at it to exercise single sign-on.
+func main() {
+	addr := flag.String("addr", ":9000", "listen address")
+	issuer := flag.String("issuer", "http://localhost:9000", "issuer URL, as reachable by the API")
+	email := flag.String("email", "dev@example.com", "email of the signed-in user")
+	name := flag.String("name", "Dev User", "name of the signed-in user")
+	flag.Parse()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatalf("❌ Failed to generate signing key: %v", err)
+	}
+
+	p := &mockProvider{
+		issuer: *issuer,
+		email:  *email,
+		name:   *name,
+		key:    key,
+		codes:  make(map[string]authRequest),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("/jwks", p.jwks)
+	mux.HandleFunc("/authorize", p.authorize)
+	mux.HandleFunc("/token", p.token)
+
+	log.Printf("✅ Mock OIDC provider for %s listening on %s", *issuer, *addr)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+const keyID = "mock-oidc"
+
+type authRequest struct {
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	email         string
+	expiresAt     time.Time
+}
+
+type mockProvider struct {
+	issuer string
+	email  string
+	name   string
+	key    *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]authRequest
+}
+
+/// ---------- DISCOVERY ---------- ///
+
+func (p *mockProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                p.issuer,
+		"authorization_endpoint":                p.issuer + "/authorize",
+		"token_endpoint":                        p.issuer + "/token",
+		"jwks_uri":                              p.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (p *mockProvider) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := p.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": keyID,
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+/// ---------- AUTHORIZATION ---------- ///
+
+func (p *mockProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirectURI := q.Get("redirect_uri")
+	target, err := url.Parse(redirectURI)
+	if err != nil || redirectURI == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if q.Get("response_type") != "code" || q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		http.Error(w, "only response_type=code with S256 PKCE is supported", http.StatusBadRequest)
+		return
+	}
+
+	email := p.email
+	if hint := q.Get("login_hint"); hint != "" {
+		email = hint
+	}
+
+	code := randomHex(16)
+	p.mu.Lock()
+	p.codes[code] = authRequest{
+		clientID:      q.Get("client_id"),
+		redirectURI:   redirectURI,
+		nonce:         q.Get("nonce"),
+		codeChallenge: q.Get("code_challenge"),
+		email:         email,
+		expiresAt:     time.Now().Add(time.Minute),
+	}
+	p.mu.Unlock()
+
+	params := target.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	target.RawQuery = params.Encode()
+
+	log.Printf("✅ Authorized %s for client %s", email, q.Get("client_id"))
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func (p *mockProvider) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request")
+		return
+	}
+
+	clientID := r.PostForm.Get("client_id")
+	if user, _, ok := r.BasicAuth(); ok {
+		if unescaped, err := url.QueryUnescape(user); err == nil {
+			clientID = unescaped
+		}
+	}
+
+	code := r.PostForm.Get("code")
+	p.mu.Lock()
+	req, ok := p.codes[code]
+	delete(p.codes, code)
+	p.mu.Unlock()
+
+	switch {
+	case r.PostForm.Get("grant_type") != "authorization_code":
+		tokenError(w, "unsupported_grant_type")
+		return
+	case !ok || time.Now().After(req.expiresAt):
+		tokenError(w, "invalid_grant")
+		return
+	case req.clientID != clientID || req.redirectURI != r.PostForm.Get("redirect_uri"):
+		tokenError(w, "invalid_grant")
+		return
+	case pkceChallenge(r.PostForm.Get("code_verifier")) != req.codeChallenge:
+		log.Printf("⚠️ PKCE verification failed for %s", req.email)
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            p.issuer,
+		"sub":            "mock|" + req.email,
+		"aud":            clientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          req.nonce,
+		"email":          req.email,
+		"email_verified": true,
+		"name":           p.name,
+	})
+	idToken.Header["kid"] = keyID
+
+	signed, err := idToken.SignedString(p.key)
+	if err != nil {
+		tokenError(w, "server_error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomHex(16),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     signed,
+	})
+}
+
+/// ---------- HELPERS ---------- ///
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
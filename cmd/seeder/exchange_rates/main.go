@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/config"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/database"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/utils"
+	"gorm.io/gorm/clause"
+)
+
+/// ---------- CSV STRUCT ---------- ///
+
+// RateCSV is one row of the rates file: 1 BASE = RATE QUOTE on DATE
+type RateCSV struct {
+	Date  string  `csv:"DATE"`
+	Base  string  `csv:"BASE"`
+	Quote string  `csv:"QUOTE"`
+	Rate  float64 `csv:"RATE"`
+}
+
+/// ---------- MAIN SEEDER ---------- ///
+
+func main() {
+	config.LoadEnv()
+	db := database.SetupDB()
+
+	filePath := "./cmd/seeder/exchange_rates/exchange_rates.csv"
+	if len(os.Args) > 1 {
+		filePath = os.Args[1]
+	}
+
+	records, err := LoadCSV(filePath)
+	if err != nil {
+		log.Fatal("❌ failed to load CSV:", err)
+	}
+
+	imported := 0
+	for i, r := range records {
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(r.Date))
+		if err != nil {
+			log.Printf("⚠️ Row %d has invalid date %q, skipping", i+2, r.Date)
+			continue
+		}
+
+		base, okBase := utils.NormalizeCurrency(r.Base)
+		quote, okQuote := utils.NormalizeCurrency(r.Quote)
+		if !okBase || !okQuote || base == quote {
+			log.Printf("⚠️ Row %d has invalid currency pair %s/%s, skipping", i+2, r.Base, r.Quote)
+			continue
+		}
+
+		if r.Rate <= 0 {
+			log.Printf("⚠️ Row %d has non-positive rate, skipping", i+2)
+			continue
+		}
+
+		// Re-running the seeder updates rates in place
+		rate := models.ExchangeRate{
+			Date:          date,
+			BaseCurrency:  base,
+			QuoteCurrency: quote,
+			Rate:          r.Rate,
+		}
+		err = db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "date"}, {Name: "base_currency"}, {Name: "quote_currency"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+		}).Create(&rate).Error
+		if err != nil {
+			log.Printf("❌ Failed to insert rate (row %d): %v", i+2, err)
+			continue
+		}
+		imported++
+	}
+
+	fmt.Printf("✅ Imported %d exchange rates successfully!\n", imported)
+}
+
+/// ---------- HELPER FUNCTIONS ---------- ///
+
+func LoadCSV(filename string) ([]*RateCSV, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// Rates file is comma separated: DATE,BASE,QUOTE,RATE
+	gocsv.SetCSVReader(func(in io.Reader) gocsv.CSVReader {
+		r := csv.NewReader(in)
+		r.Comma = ','
+		r.TrimLeadingSpace = true
+		return r
+	})
+
+	var records []*RateCSV
+	if err := gocsv.UnmarshalFile(file, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
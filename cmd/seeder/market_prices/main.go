@@ -6,6 +6,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/gocarina/gocsv"
@@ -43,7 +44,7 @@ type Region struct {
 	DeletedAt *time.Time
 }
 
-// SubRegions table (optional – we’ll skip unless you want to map SizeRange)
+// SubRegions table (holds the CSV size/weight range within a region)
 type SubRegion struct {
 	ID        uint `gorm:"primaryKey"`
 	RegionID  uint
@@ -73,6 +74,7 @@ type Price struct {
 	SeafoodID uint
 	Date      time.Time
 	Price     float64
+	Currency  string
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time
@@ -131,9 +133,12 @@ func main() {
 			continue
 		}
 
+		// Collapse stray whitespace so repeated size ranges share one sub-region
+		sizeRange := strings.Join(strings.Fields(r.SizeRange), " ")
+
 		// Create a unique key for deduplication
 		key := fmt.Sprintf("%s|%s|%s|%s|%s|%.2f|%s",
-			r.Product, r.Category, r.Country, priceUnit, dateStr, priceValue, r.SizeRange)
+			r.Product, r.Category, r.Country, priceUnit, dateStr, priceValue, sizeRange)
 
 		if seen[key] {
 			// Skip duplicate row
@@ -153,12 +158,22 @@ func main() {
 		var region Region
 		db.Where("region = ?", r.Country).FirstOrCreate(&region, Region{Region: r.Country})
 
+		// --- Insert Sub-region (size/weight range) ---
+		var subRegionID *uint
+		if sizeRange != "" {
+			var subRegion SubRegion
+			db.Where("region_id = ? AND sub_region = ?", region.ID, sizeRange).
+				FirstOrCreate(&subRegion, SubRegion{RegionID: region.ID, SubRegion: sizeRange})
+			subRegionID = &subRegion.ID
+		}
+
 		// --- Insert Seafood ---
 		seafood := Seafood{
-			SpeciesID:  species.ID,
-			RegionID:   region.ID,
-			CategoryID: category.ID,
-			PriceUnit:  priceUnit,
+			SpeciesID:   species.ID,
+			RegionID:    region.ID,
+			SubRegionID: subRegionID,
+			CategoryID:  category.ID,
+			PriceUnit:   priceUnit,
 		}
 		db.Create(&seafood)
 
@@ -167,6 +182,7 @@ func main() {
 			SeafoodID: seafood.ID,
 			Date:      parsedDate,
 			Price:     priceValue,
+			Currency:  "EUR",
 		}
 		db.Create(&price)
 	}
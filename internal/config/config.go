@@ -2,6 +2,10 @@ package config
 
 import (
 	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -12,3 +16,118 @@ func LoadEnv() {
 		log.Println("Warning: No .env file found")
 	}
 }
+
+// RequireEmailVerification reports whether data routes are closed to unverified
+// accounts, set with REQUIRE_EMAIL_VERIFICATION (default false)
+func RequireEmailVerification() bool {
+	return getBool("REQUIRE_EMAIL_VERIFICATION", false)
+}
+
+// MaxFailedLogins is how many consecutive wrong passwords lock an account,
+// set with LOGIN_MAX_FAILED_ATTEMPTS (default 5)
+func MaxFailedLogins() int {
+	return getInt("LOGIN_MAX_FAILED_ATTEMPTS", 5)
+}
+
+// LoginLockoutDuration is how long a locked account stays locked unless unlocked
+// by email, set with LOGIN_LOCKOUT_DURATION (default 15m)
+func LoginLockoutDuration() time.Duration {
+	return getDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute)
+}
+
+// MaxFailedLoginsPerIP is how many failed logins one client IP may make within
+// LoginAttemptWindow, set with LOGIN_IP_MAX_FAILED_ATTEMPTS (default 20)
+func MaxFailedLoginsPerIP() int {
+	return getInt("LOGIN_IP_MAX_FAILED_ATTEMPTS", 20)
+}
+
+// LoginAttemptWindow is the sliding window for the per-IP limit, set with
+// LOGIN_ATTEMPT_WINDOW (default 15m)
+func LoginAttemptWindow() time.Duration {
+	return getDuration("LOGIN_ATTEMPT_WINDOW", 15*time.Minute)
+}
+
+// MaxResetEmailsPerHour caps password reset emails per address, set with
+// PASSWORD_RESET_EMAILS_PER_HOUR (default 3)
+func MaxResetEmailsPerHour() int {
+	return getInt("PASSWORD_RESET_EMAILS_PER_HOUR", 3)
+}
+
+// MaxVerificationEmailsPerHour caps resent verification emails per address,
+// set with VERIFICATION_EMAILS_PER_HOUR (default 3)
+func MaxVerificationEmailsPerHour() int {
+	return getInt("VERIFICATION_EMAILS_PER_HOUR", 3)
+}
+
+// MaxOIDCLoginsPerIP caps the single sign-on logins an IP can start per
+// LOGIN_ATTEMPT_WINDOW, set with OIDC_LOGIN_IP_MAX_ATTEMPTS (default 30)
+func MaxOIDCLoginsPerIP() int {
+	return getInt("OIDC_LOGIN_IP_MAX_ATTEMPTS", 30)
+}
+
+// OIDCProviderConfig is one OpenID Connect identity provider
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	TrustMFA     bool
+}
+
+// OIDCProviders reads the providers named in OIDC_PROVIDERS (comma-separated).
+// Each name NAME is configured with OIDC_NAME_ISSUER, OIDC_NAME_CLIENT_ID,
+// OIDC_NAME_CLIENT_SECRET, OIDC_NAME_REDIRECT_URL and optionally OIDC_NAME_SCOPES
+// and OIDC_NAME_TRUST_MFA. With TRUST_MFA=true the provider's own multi-factor
+// login stands in for the user's TOTP; otherwise users with TOTP enabled still
+// complete POST /login/2fa. Providers without an issuer or client id are skipped.
+func OIDCProviders() []OIDCProviderConfig {
+	var providers []OIDCProviderConfig
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		p := OIDCProviderConfig{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+			TrustMFA:     getBool(prefix+"TRUST_MFA", false),
+		}
+		if p.Issuer == "" || p.ClientID == "" {
+			log.Printf("Warning: OIDC provider %q is missing an issuer or client id", name)
+			continue
+		}
+		if len(p.Scopes) == 0 {
+			p.Scopes = []string{"openid", "email", "profile"}
+		}
+		providers = append(providers, p)
+	}
+	return providers
+}
+
+func getBool(key string, fallback bool) bool {
+	if v, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return v
+	}
+	return fallback
+}
+
+func getInt(key string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil && v > 0 {
+		return v
+	}
+	return fallback
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil && d > 0 {
+		return d
+	}
+	return fallback
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+)
+
+const apiKeyColumns = `id, user_id, organization_id, name, prefix, key_hash, scopes, created_at, last_used_at, expires_at, revoked_at`
+
+func scanAPIKey(row rowScanner) (*models.APIKey, error) {
+	k := &models.APIKey{}
+	var scopes string
+	err := row.Scan(
+		&k.ID,
+		&k.UserID,
+		&k.OrganizationID,
+		&k.Name,
+		&k.Prefix,
+		&k.KeyHash,
+		&scopes,
+		&k.CreatedAt,
+		&k.LastUsedAt,
+		&k.ExpiresAt,
+		&k.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	k.Scopes = []string{}
+	if scopes != "" {
+		k.Scopes = strings.Split(scopes, ",")
+	}
+	return k, nil
+}
+
+func CreateAPIKey(key *models.APIKey) error {
+	query := `INSERT INTO api_keys (user_id, organization_id, name, prefix, key_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at`
+	return sqlDB.QueryRow(query,
+		key.UserID,
+		key.OrganizationID,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		strings.Join(key.Scopes, ","),
+		key.ExpiresAt,
+	).Scan(&key.ID, &key.CreatedAt)
+}
+
+func GetAPIKeyByHash(hash string) (*models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = $1`
+	return scanAPIKey(sqlDB.QueryRow(query, hash))
+}
+
+// GetAPIKeys returns the keys a user created plus those shared with their
+// organizations, including revoked ones, newest first
+func GetAPIKeys(userID int) ([]models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys
+		WHERE user_id = $1
+		   OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = $1)
+		ORDER BY created_at DESC`
+	rows, err := sqlDB.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *k)
+	}
+	return keys, rows.Err()
+}
+
+func TouchAPIKey(id int) error {
+	query := `UPDATE api_keys SET last_used_at = CURRENT_TIMESTAMP WHERE id = $1`
+	_, err := sqlDB.Exec(query, id)
+	return err
+}
+
+// RevokeAPIKey revokes a key created by the user, or shared with an organization the user owns
+func RevokeAPIKey(id, userID int) error {
+	query := `UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND revoked_at IS NULL
+		  AND (user_id = $2 OR organization_id IN (
+			SELECT organization_id FROM organization_members WHERE user_id = $2 AND role = $3))`
+	res, err := sqlDB.Exec(query, id, userID, models.OrgRoleOwner)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+)
+
+// WriteAuditLog appends an entry to the audit log
+func WriteAuditLog(entry *models.AuditLogEntry) error {
+	query := `INSERT INTO audit_log (actor_user_id, actor_email, action, target_type, target_id, ip_address, user_agent, outcome, details)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at`
+	return sqlDB.QueryRow(query,
+		entry.ActorUserID,
+		entry.ActorEmail,
+		entry.Action,
+		entry.TargetType,
+		entry.TargetID,
+		entry.IPAddress,
+		entry.UserAgent,
+		entry.Outcome,
+		entry.Details,
+	).Scan(&entry.ID, &entry.CreatedAt)
+}
+
+// ListAuditLog returns a page of matching entries, newest first, along with the total count
+func ListAuditLog(filter models.AuditLogFilter, limit, offset int) ([]models.AuditLogEntry, int64, error) {
+	var filterConditions []string
+	var filterArgs []interface{}
+	argIndex := 1
+
+	add := func(condition string, arg interface{}) {
+		filterConditions = append(filterConditions, fmt.Sprintf(condition, argIndex))
+		filterArgs = append(filterArgs, arg)
+		argIndex++
+	}
+
+	if filter.ActorUserID != nil {
+		add("actor_user_id = $%d", *filter.ActorUserID)
+	}
+	if filter.ActorEmail != "" {
+		add("LOWER(actor_email) = LOWER($%d)", filter.ActorEmail)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.Outcome != "" {
+		add("outcome = $%d", filter.Outcome)
+	}
+	if filter.TargetType != "" {
+		add("target_type = $%d", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		add("target_id = $%d", filter.TargetID)
+	}
+	if filter.IPAddress != "" {
+		add("ip_address = $%d", filter.IPAddress)
+	}
+	if filter.From != nil {
+		add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("created_at < $%d", *filter.To)
+	}
+
+	whereClause := "TRUE"
+	if len(filterConditions) > 0 {
+		whereClause = strings.Join(filterConditions, " AND ")
+	}
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM audit_log WHERE ` + whereClause
+	if err := sqlDB.QueryRow(countQuery, filterArgs...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`SELECT id, actor_user_id, actor_email, action, target_type, target_id, ip_address, user_agent, outcome, details, created_at
+		FROM audit_log
+		WHERE %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d`, whereClause, argIndex, argIndex+1)
+	rows, err := sqlDB.Query(query, append(filterArgs, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []models.AuditLogEntry{}
+	for rows.Next() {
+		var e models.AuditLogEntry
+		err := rows.Scan(
+			&e.ID,
+			&e.ActorUserID,
+			&e.ActorEmail,
+			&e.Action,
+			&e.TargetType,
+			&e.TargetID,
+			&e.IPAddress,
+			&e.UserAgent,
+			&e.Outcome,
+			&e.Details,
+			&e.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, total, rows.Err()
+}
@@ -64,6 +64,210 @@ func SetupDB() *gorm.DB {
 	)`
 	sqlDB.Exec(createTokensTable)
 
+	// Track password changes so older access tokens can be rejected
+	sqlDB.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP`)
+
+	// Add role column to users (viewer, analyst or admin)
+	sqlDB.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'viewer'`)
+
+	// Add email_verified_at column to users
+	sqlDB.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP`)
+
+	// Create email_verification_tokens table
+	createVerificationTokensTable := `
+	CREATE TABLE IF NOT EXISTS email_verification_tokens (
+		id SERIAL PRIMARY KEY,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		token VARCHAR(255) UNIQUE NOT NULL,
+		expires_at TIMESTAMP NOT NULL,
+		used BOOLEAN DEFAULT FALSE,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`
+	sqlDB.Exec(createVerificationTokensTable)
+
+	// An email change waits in pending_email until the new address is verified;
+	// the token records which address it was sent to
+	sqlDB.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email VARCHAR(255)`)
+	sqlDB.Exec(`ALTER TABLE email_verification_tokens ADD COLUMN IF NOT EXISTS email VARCHAR(255)`)
+
+	// Add TOTP columns to users
+	sqlDB.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret VARCHAR(64)`)
+	sqlDB.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled_at TIMESTAMP`)
+	sqlDB.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_step BIGINT`)
+
+	// Create recovery_codes table
+	createRecoveryCodesTable := `
+	CREATE TABLE IF NOT EXISTS recovery_codes (
+		id SERIAL PRIMARY KEY,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		code_hash VARCHAR(64) NOT NULL,
+		used_at TIMESTAMP,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`
+	sqlDB.Exec(createRecoveryCodesTable)
+	sqlDB.Exec(`CREATE INDEX IF NOT EXISTS idx_recovery_codes_user_id ON recovery_codes(user_id)`)
+
+	// Add lockout columns to users
+	sqlDB.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS failed_login_count INTEGER NOT NULL DEFAULT 0`)
+	sqlDB.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP`)
+
+	// Create login_attempts table
+	createLoginAttemptsTable := `
+	CREATE TABLE IF NOT EXISTS login_attempts (
+		id SERIAL PRIMARY KEY,
+		email VARCHAR(255) NOT NULL,
+		ip_address VARCHAR(45) NOT NULL,
+		success BOOLEAN NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`
+	sqlDB.Exec(createLoginAttemptsTable)
+	sqlDB.Exec(`CREATE INDEX IF NOT EXISTS idx_login_attempts_ip_created ON login_attempts(ip_address, created_at)`)
+
+	// Create account_unlock_tokens table
+	createUnlockTokensTable := `
+	CREATE TABLE IF NOT EXISTS account_unlock_tokens (
+		id SERIAL PRIMARY KEY,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		token VARCHAR(255) UNIQUE NOT NULL,
+		expires_at TIMESTAMP NOT NULL,
+		used BOOLEAN DEFAULT FALSE,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`
+	sqlDB.Exec(createUnlockTokensTable)
+
+	// Create sessions table (one row per signed-in device)
+	createSessionsTable := `
+	CREATE TABLE IF NOT EXISTS sessions (
+		id SERIAL PRIMARY KEY,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		refresh_token_hash VARCHAR(64) UNIQUE NOT NULL,
+		device_name VARCHAR(255) NOT NULL DEFAULT '',
+		user_agent TEXT NOT NULL DEFAULT '',
+		ip_address VARCHAR(64) NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		last_used_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		expires_at TIMESTAMP NOT NULL,
+		revoked_at TIMESTAMP
+	)`
+	sqlDB.Exec(createSessionsTable)
+	sqlDB.Exec(`CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id)`)
+
+	// Create api_keys table (only the SHA-256 of each key is stored)
+	createAPIKeysTable := `
+	CREATE TABLE IF NOT EXISTS api_keys (
+		id SERIAL PRIMARY KEY,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		name VARCHAR(100) NOT NULL,
+		prefix VARCHAR(16) NOT NULL,
+		key_hash VARCHAR(64) UNIQUE NOT NULL,
+		scopes TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		last_used_at TIMESTAMP,
+		expires_at TIMESTAMP,
+		revoked_at TIMESTAMP
+	)`
+	sqlDB.Exec(createAPIKeysTable)
+	sqlDB.Exec(`CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id)`)
+
+	// Create organizations table
+	createOrganizationsTable := `
+	CREATE TABLE IF NOT EXISTS organizations (
+		id SERIAL PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`
+	sqlDB.Exec(createOrganizationsTable)
+
+	// Create organization_members table
+	createMembersTable := `
+	CREATE TABLE IF NOT EXISTS organization_members (
+		organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		role VARCHAR(20) NOT NULL DEFAULT 'member',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (organization_id, user_id)
+	)`
+	sqlDB.Exec(createMembersTable)
+	sqlDB.Exec(`CREATE INDEX IF NOT EXISTS idx_organization_members_user_id ON organization_members(user_id)`)
+
+	// Create organization_invites table (only the SHA-256 of each token is stored)
+	createInvitesTable := `
+	CREATE TABLE IF NOT EXISTS organization_invites (
+		id SERIAL PRIMARY KEY,
+		organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+		email VARCHAR(255) NOT NULL,
+		role VARCHAR(20) NOT NULL DEFAULT 'member',
+		token_hash VARCHAR(64) UNIQUE NOT NULL,
+		invited_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+		expires_at TIMESTAMP NOT NULL,
+		accepted_at TIMESTAMP,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`
+	sqlDB.Exec(createInvitesTable)
+
+	// API keys can be shared with an organization
+	sqlDB.Exec(`ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS organization_id INTEGER REFERENCES organizations(id) ON DELETE CASCADE`)
+
+	// Create oidc_login_states table (pending SSO logins, deleted when used)
+	createOIDCStatesTable := `
+	CREATE TABLE IF NOT EXISTS oidc_login_states (
+		id SERIAL PRIMARY KEY,
+		state_hash VARCHAR(64) UNIQUE NOT NULL,
+		provider VARCHAR(64) NOT NULL,
+		nonce VARCHAR(255) NOT NULL,
+		code_verifier VARCHAR(255) NOT NULL,
+		expires_at TIMESTAMP NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`
+	sqlDB.Exec(createOIDCStatesTable)
+	sqlDB.Exec(`ALTER TABLE oidc_login_states ADD COLUMN IF NOT EXISTS ip_address VARCHAR(45) NOT NULL DEFAULT ''`)
+	sqlDB.Exec(`CREATE INDEX IF NOT EXISTS idx_oidc_login_states_ip_created ON oidc_login_states(ip_address, created_at)`)
+	sqlDB.Exec(`CREATE INDEX IF NOT EXISTS idx_oidc_login_states_expires_at ON oidc_login_states(expires_at)`)
+
+	// Create user_identities table (identity provider accounts linked to users)
+	createIdentitiesTable := `
+	CREATE TABLE IF NOT EXISTS user_identities (
+		id SERIAL PRIMARY KEY,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		issuer VARCHAR(255) NOT NULL,
+		subject VARCHAR(255) NOT NULL,
+		email VARCHAR(255) NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (issuer, subject)
+	)`
+	sqlDB.Exec(createIdentitiesTable)
+	sqlDB.Exec(`CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id)`)
+
+	// Create audit_log table. It has no foreign keys so entries outlive the
+	// users they mention, and a trigger rejects updates and deletes.
+	createAuditLogTable := `
+	CREATE TABLE IF NOT EXISTS audit_log (
+		id BIGSERIAL PRIMARY KEY,
+		actor_user_id INTEGER,
+		actor_email VARCHAR(255) NOT NULL DEFAULT '',
+		action VARCHAR(64) NOT NULL,
+		target_type VARCHAR(64) NOT NULL DEFAULT '',
+		target_id VARCHAR(64) NOT NULL DEFAULT '',
+		ip_address VARCHAR(45) NOT NULL DEFAULT '',
+		user_agent TEXT NOT NULL DEFAULT '',
+		outcome VARCHAR(20) NOT NULL,
+		details TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`
+	sqlDB.Exec(createAuditLogTable)
+	sqlDB.Exec(`CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at)`)
+	sqlDB.Exec(`CREATE INDEX IF NOT EXISTS idx_audit_log_actor_user_id ON audit_log(actor_user_id)`)
+	sqlDB.Exec(`
+	CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+	BEGIN
+		RAISE EXCEPTION 'audit_log is append-only';
+	END;
+	$$ LANGUAGE plpgsql`)
+	sqlDB.Exec(`DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log`)
+	sqlDB.Exec(`CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE ON audit_log
+		FOR EACH ROW EXECUTE FUNCTION audit_log_append_only()`)
+
 	return db
 }
 
@@ -113,28 +317,32 @@ func CloseDB() {
 }
 
 func CreateUser(user *models.User) error {
-	query := `INSERT INTO users (email, password, name) VALUES ($1, $2, $3) RETURNING id, created_at`
-	return sqlDB.QueryRow(query, user.Email, user.Password, user.Name).Scan(&user.ID, &user.CreatedAt)
+	if user.Role == "" {
+		user.Role = models.RoleViewer
+	}
+	query := `INSERT INTO users (email, password, name, role) VALUES ($1, $2, $3, $4) RETURNING id, created_at`
+	return sqlDB.QueryRow(query, user.Email, user.Password, user.Name, user.Role).Scan(&user.ID, &user.CreatedAt)
 }
 
-func GetUserByEmail(email string) (*models.User, error) {
+const userColumns = `id, email, pending_email, password, name, role, email_verified_at, totp_enabled_at, locked_until, created_at, password_changed_at`
+
+func scanUser(row rowScanner) (*models.User, error) {
 	user := &models.User{}
-	query := `SELECT id, email, password, name, created_at FROM users WHERE email = $1`
-	err := sqlDB.QueryRow(query, email).Scan(&user.ID, &user.Email, &user.Password, &user.Name, &user.CreatedAt)
+	err := row.Scan(&user.ID, &user.Email, &user.PendingEmail, &user.Password, &user.Name, &user.Role, &user.EmailVerifiedAt, &user.TOTPEnabledAt, &user.LockedUntil, &user.CreatedAt, &user.PasswordChangedAt)
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
+func GetUserByEmail(email string) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
+	return scanUser(sqlDB.QueryRow(query, email))
+}
+
 func GetUserByID(id int) (*models.User, error) {
-	user := &models.User{}
-	query := `SELECT id, email, password, name, created_at FROM users WHERE id = $1`
-	err := sqlDB.QueryRow(query, id).Scan(&user.ID, &user.Email, &user.Password, &user.Name, &user.CreatedAt)
-	if err != nil {
-		return nil, err
-	}
-	return user, nil
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	return scanUser(sqlDB.QueryRow(query, id))
 }
 
 func CreatePasswordResetToken(userID int, token string, expiresAt time.Time) error {
@@ -160,6 +368,15 @@ func GetPasswordResetToken(token string) (*models.PasswordResetToken, error) {
 	return resetToken, nil
 }
 
+// CountRecentPasswordResetTokens counts the reset tokens issued to a user within the window
+func CountRecentPasswordResetTokens(userID int, window time.Duration) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM password_reset_tokens
+		WHERE user_id = $1 AND created_at >= CURRENT_TIMESTAMP - $2 * INTERVAL '1 second'`
+	err := sqlDB.QueryRow(query, userID, window.Seconds()).Scan(&count)
+	return count, err
+}
+
 func MarkTokenAsUsed(token string) error {
 	query := `UPDATE password_reset_tokens SET used = true WHERE token = $1`
 	_, err := sqlDB.Exec(query, token)
@@ -167,7 +384,125 @@ func MarkTokenAsUsed(token string) error {
 }
 
 func UpdateUserPassword(userID int, hashedPassword string) error {
-	query := `UPDATE users SET password = $1 WHERE id = $2`
+	query := `UPDATE users SET password = $1, password_changed_at = CURRENT_TIMESTAMP WHERE id = $2`
 	_, err := sqlDB.Exec(query, hashedPassword, userID)
 	return err
 }
+
+func UpdateUserRole(userID int, role string) error {
+	query := `UPDATE users SET role = $1 WHERE id = $2`
+	res, err := sqlDB.Exec(query, role, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func UpdateUserRoleByEmail(email, role string) error {
+	query := `UPDATE users SET role = $1 WHERE email = $2`
+	res, err := sqlDB.Exec(query, role, email)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func UpdateUserName(userID int, name string) error {
+	query := `UPDATE users SET name = $1 WHERE id = $2`
+	_, err := sqlDB.Exec(query, name, userID)
+	return err
+}
+
+// SetPendingEmail records an email change awaiting verification. Links sent
+// for an earlier change stop working; the current email is left alone.
+func SetPendingEmail(userID int, email string) error {
+	tx, err := sqlDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE users SET pending_email = $1 WHERE id = $2`, email, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE email_verification_tokens SET used = true
+		WHERE user_id = $1 AND email IS NOT NULL AND used = false`, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ApplyPendingEmail makes the pending email the user's verified email. It
+// returns sql.ErrNoRows when email is no longer the pending one.
+func ApplyPendingEmail(userID int, email string) error {
+	query := `UPDATE users SET email = pending_email, pending_email = NULL, email_verified_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND pending_email = $2`
+	res, err := sqlDB.Exec(query, userID, email)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteUser removes the user; tokens, sessions, API keys and recovery codes
+// go with it through ON DELETE CASCADE
+func DeleteUser(userID int) error {
+	tx, err := sqlDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var email string
+	if err := tx.QueryRow(`DELETE FROM users WHERE id = $1 RETURNING email`, userID).Scan(&email); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM login_attempts WHERE email = LOWER($1)`, email); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func CountUsersWithRole(role string) (int, error) {
+	var count int
+	err := sqlDB.QueryRow(`SELECT COUNT(*) FROM users WHERE role = $1`, role).Scan(&count)
+	return count, err
+}
+
+// ListUsers returns a page of users ordered by id along with the total count
+func ListUsers(limit, offset int) ([]models.User, int64, error) {
+	var total int64
+	if err := sqlDB.QueryRow(`SELECT COUNT(*) FROM users`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users ORDER BY id ASC LIMIT $1 OFFSET $2`
+	rows, err := sqlDB.Query(query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		users = append(users, *user)
+	}
+	return users, total, rows.Err()
+}
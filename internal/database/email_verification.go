@@ -0,0 +1,55 @@
+package database
+
+import (
+	"time"
+
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+)
+
+// CreateEmailVerificationToken saves a verification token. email is the
+// pending address for an email change, or empty to verify the current one.
+func CreateEmailVerificationToken(userID int, token, email string, expiresAt time.Time) error {
+	query := `INSERT INTO email_verification_tokens (user_id, token, email, expires_at) VALUES ($1, $2, NULLIF($3, ''), $4)`
+	_, err := sqlDB.Exec(query, userID, token, email, expiresAt)
+	return err
+}
+
+// CountRecentEmailVerificationTokens counts the verification tokens issued to a
+// user's current address within the window
+func CountRecentEmailVerificationTokens(userID int, window time.Duration) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM email_verification_tokens
+		WHERE user_id = $1 AND email IS NULL AND created_at >= CURRENT_TIMESTAMP - $2 * INTERVAL '1 second'`
+	err := sqlDB.QueryRow(query, userID, window.Seconds()).Scan(&count)
+	return count, err
+}
+
+func GetEmailVerificationToken(token string) (*models.EmailVerificationToken, error) {
+	verificationToken := &models.EmailVerificationToken{}
+	query := `SELECT id, user_id, token, COALESCE(email, ''), expires_at, used, created_at FROM email_verification_tokens WHERE token = $1`
+	err := sqlDB.QueryRow(query, token).Scan(
+		&verificationToken.ID,
+		&verificationToken.UserID,
+		&verificationToken.Token,
+		&verificationToken.Email,
+		&verificationToken.ExpiresAt,
+		&verificationToken.Used,
+		&verificationToken.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return verificationToken, nil
+}
+
+func MarkEmailVerificationTokenAsUsed(token string) error {
+	query := `UPDATE email_verification_tokens SET used = true WHERE token = $1`
+	_, err := sqlDB.Exec(query, token)
+	return err
+}
+
+func MarkUserEmailVerified(userID int) error {
+	query := `UPDATE users SET email_verified_at = CURRENT_TIMESTAMP WHERE id = $1 AND email_verified_at IS NULL`
+	_, err := sqlDB.Exec(query, userID)
+	return err
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"time"
+
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+)
+
+func RecordLoginAttempt(email, ipAddress string, success bool) error {
+	query := `INSERT INTO login_attempts (email, ip_address, success) VALUES ($1, $2, $3)`
+	_, err := sqlDB.Exec(query, email, ipAddress, success)
+	return err
+}
+
+// CountRecentFailedLoginsByIP counts the failed logins from an IP within the window
+func CountRecentFailedLoginsByIP(ipAddress string, window time.Duration) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM login_attempts
+		WHERE ip_address = $1 AND success = false AND created_at >= CURRENT_TIMESTAMP - $2 * INTERVAL '1 second'`
+	err := sqlDB.QueryRow(query, ipAddress, window.Seconds()).Scan(&count)
+	return count, err
+}
+
+// IncrementFailedLogins bumps the user's consecutive failure count and returns the new value
+func IncrementFailedLogins(userID int) (int, error) {
+	var count int
+	query := `UPDATE users SET failed_login_count = failed_login_count + 1 WHERE id = $1 RETURNING failed_login_count`
+	err := sqlDB.QueryRow(query, userID).Scan(&count)
+	return count, err
+}
+
+// LockUser locks the account until the given time and starts a fresh failure count
+func LockUser(userID int, until time.Time) error {
+	query := `UPDATE users SET locked_until = $1, failed_login_count = 0 WHERE id = $2`
+	_, err := sqlDB.Exec(query, until, userID)
+	return err
+}
+
+// ClearLoginFailures resets the failure count and lifts any lock
+func ClearLoginFailures(userID int) error {
+	query := `UPDATE users SET failed_login_count = 0, locked_until = NULL WHERE id = $1`
+	_, err := sqlDB.Exec(query, userID)
+	return err
+}
+
+func CreateUnlockToken(userID int, token string, expiresAt time.Time) error {
+	query := `INSERT INTO account_unlock_tokens (user_id, token, expires_at) VALUES ($1, $2, $3)`
+	_, err := sqlDB.Exec(query, userID, token, expiresAt)
+	return err
+}
+
+func GetUnlockToken(token string) (*models.AccountUnlockToken, error) {
+	unlockToken := &models.AccountUnlockToken{}
+	query := `SELECT id, user_id, token, expires_at, used, created_at FROM account_unlock_tokens WHERE token = $1`
+	err := sqlDB.QueryRow(query, token).Scan(
+		&unlockToken.ID,
+		&unlockToken.UserID,
+		&unlockToken.Token,
+		&unlockToken.ExpiresAt,
+		&unlockToken.Used,
+		&unlockToken.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return unlockToken, nil
+}
+
+func MarkUnlockTokenAsUsed(token string) error {
+	query := `UPDATE account_unlock_tokens SET used = true WHERE token = $1`
+	_, err := sqlDB.Exec(query, token)
+	return err
+}
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"time"
+
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+)
+
+// CreateOIDCLoginState saves a pending login, first deleting any that expired
+// without being used so abandoned logins do not pile up
+func CreateOIDCLoginState(state *models.OIDCLoginState) error {
+	if _, err := sqlDB.Exec(`DELETE FROM oidc_login_states WHERE expires_at < $1`, time.Now()); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO oidc_login_states (state_hash, provider, nonce, code_verifier, ip_address, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+	return sqlDB.QueryRow(query,
+		state.StateHash,
+		state.Provider,
+		state.Nonce,
+		state.CodeVerifier,
+		state.IPAddress,
+		state.ExpiresAt,
+	).Scan(&state.ID, &state.CreatedAt)
+}
+
+// CountRecentOIDCLoginStatesByIP counts the logins an IP has started within the window
+func CountRecentOIDCLoginStatesByIP(ipAddress string, window time.Duration) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM oidc_login_states
+		WHERE ip_address = $1 AND created_at >= CURRENT_TIMESTAMP - $2 * INTERVAL '1 second'`
+	err := sqlDB.QueryRow(query, ipAddress, window.Seconds()).Scan(&count)
+	return count, err
+}
+
+// ConsumeOIDCLoginState deletes and returns the pending login for a state, so each state works once
+func ConsumeOIDCLoginState(stateHash, provider string) (*models.OIDCLoginState, error) {
+	s := &models.OIDCLoginState{}
+	query := `DELETE FROM oidc_login_states WHERE state_hash = $1 AND provider = $2
+		RETURNING id, state_hash, provider, nonce, code_verifier, ip_address, expires_at, created_at`
+	err := sqlDB.QueryRow(query, stateHash, provider).Scan(
+		&s.ID,
+		&s.StateHash,
+		&s.Provider,
+		&s.Nonce,
+		&s.CodeVerifier,
+		&s.IPAddress,
+		&s.ExpiresAt,
+		&s.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// GetUserByIdentity returns the user linked to an identity provider subject
+func GetUserByIdentity(issuer, subject string) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users
+		WHERE id = (SELECT user_id FROM user_identities WHERE issuer = $1 AND subject = $2)`
+	return scanUser(sqlDB.QueryRow(query, issuer, subject))
+}
+
+// GetUserByEmailFold returns the user whose email matches ignoring case,
+// preferring an exact match when addresses differ only by case
+func GetUserByEmailFold(email string) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users
+		WHERE LOWER(email) = LOWER($1)
+		ORDER BY email = $1 DESC, id ASC
+		LIMIT 1`
+	return scanUser(sqlDB.QueryRow(query, email))
+}
+
+// LinkUserIdentity links an identity provider subject to a user
+func LinkUserIdentity(userID int, issuer, subject, email string) error {
+	query := `INSERT INTO user_identities (user_id, issuer, subject, email) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (issuer, subject) DO NOTHING`
+	_, err := sqlDB.Exec(query, userID, issuer, subject, email)
+	return err
+}
@@ -0,0 +1,242 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+)
+
+// CreateOrganization creates the organization and makes the creator its owner
+func CreateOrganization(org *models.Organization, ownerID int) error {
+	tx, err := sqlDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO organizations (name, created_by) VALUES ($1, $2) RETURNING id, created_at`
+	if err := tx.QueryRow(query, org.Name, ownerID).Scan(&org.ID, &org.CreatedAt); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO organization_members (organization_id, user_id, role) VALUES ($1, $2, $3)`,
+		org.ID, ownerID, models.OrgRoleOwner); err != nil {
+		return err
+	}
+	org.CreatedBy = &ownerID
+	org.Role = models.OrgRoleOwner
+	return tx.Commit()
+}
+
+// GetUserOrganizations returns the organizations the user belongs to, with their role in each
+func GetUserOrganizations(userID int) ([]models.Organization, error) {
+	query := `SELECT o.id, o.name, o.created_by, o.created_at, m.role
+		FROM organizations o
+		JOIN organization_members m ON m.organization_id = o.id
+		WHERE m.user_id = $1
+		ORDER BY o.name ASC`
+	rows, err := sqlDB.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orgs := []models.Organization{}
+	for rows.Next() {
+		var o models.Organization
+		if err := rows.Scan(&o.ID, &o.Name, &o.CreatedBy, &o.CreatedAt, &o.Role); err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, o)
+	}
+	return orgs, rows.Err()
+}
+
+// GetOrganizationForMember returns the organization if the user is a member of it
+func GetOrganizationForMember(orgID, userID int) (*models.Organization, error) {
+	o := &models.Organization{}
+	query := `SELECT o.id, o.name, o.created_by, o.created_at, m.role
+		FROM organizations o
+		JOIN organization_members m ON m.organization_id = o.id
+		WHERE o.id = $1 AND m.user_id = $2`
+	err := sqlDB.QueryRow(query, orgID, userID).Scan(&o.ID, &o.Name, &o.CreatedBy, &o.CreatedAt, &o.Role)
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func UpdateOrganizationName(orgID int, name string) error {
+	query := `UPDATE organizations SET name = $1 WHERE id = $2`
+	_, err := sqlDB.Exec(query, name, orgID)
+	return err
+}
+
+// DeleteOrganization removes the organization along with its memberships,
+// invites and shared API keys
+func DeleteOrganization(orgID int) error {
+	query := `DELETE FROM organizations WHERE id = $1`
+	_, err := sqlDB.Exec(query, orgID)
+	return err
+}
+
+func GetOrganizationMembers(orgID int) ([]models.OrganizationMember, error) {
+	query := `SELECT u.id, u.email, u.name, m.role, m.created_at
+		FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.organization_id = $1
+		ORDER BY m.created_at ASC`
+	rows, err := sqlDB.Query(query, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []models.OrganizationMember{}
+	for rows.Next() {
+		var m models.OrganizationMember
+		if err := rows.Scan(&m.UserID, &m.Email, &m.Name, &m.Role, &m.JoinedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+// GetMemberRole returns the user's role in the organization
+func GetMemberRole(orgID, userID int) (string, error) {
+	var role string
+	query := `SELECT role FROM organization_members WHERE organization_id = $1 AND user_id = $2`
+	err := sqlDB.QueryRow(query, orgID, userID).Scan(&role)
+	return role, err
+}
+
+func CountOrganizationOwners(orgID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM organization_members WHERE organization_id = $1 AND role = $2`
+	err := sqlDB.QueryRow(query, orgID, models.OrgRoleOwner).Scan(&count)
+	return count, err
+}
+
+func UpdateMemberRole(orgID, userID int, role string) error {
+	query := `UPDATE organization_members SET role = $1 WHERE organization_id = $2 AND user_id = $3`
+	res, err := sqlDB.Exec(query, role, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func RemoveOrganizationMember(orgID, userID int) error {
+	query := `DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2`
+	res, err := sqlDB.Exec(query, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ----------- Invites -----------
+
+const inviteColumns = `id, organization_id, email, role, token_hash, invited_by, expires_at, accepted_at, created_at`
+
+func scanInvite(row rowScanner) (*models.OrganizationInvite, error) {
+	i := &models.OrganizationInvite{}
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Email,
+		&i.Role,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.ExpiresAt,
+		&i.AcceptedAt,
+		&i.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+func CreateOrganizationInvite(invite *models.OrganizationInvite) error {
+	query := `INSERT INTO organization_invites (organization_id, email, role, token_hash, invited_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+	return sqlDB.QueryRow(query,
+		invite.OrganizationID,
+		invite.Email,
+		invite.Role,
+		invite.TokenHash,
+		invite.InvitedBy,
+		invite.ExpiresAt,
+	).Scan(&invite.ID, &invite.CreatedAt)
+}
+
+func GetOrganizationInviteByHash(hash string) (*models.OrganizationInvite, error) {
+	query := `SELECT ` + inviteColumns + ` FROM organization_invites WHERE token_hash = $1`
+	return scanInvite(sqlDB.QueryRow(query, hash))
+}
+
+// GetPendingInvites returns the unaccepted, unexpired invites of an organization
+func GetPendingInvites(orgID int) ([]models.OrganizationInvite, error) {
+	query := `SELECT ` + inviteColumns + ` FROM organization_invites
+		WHERE organization_id = $1 AND accepted_at IS NULL AND expires_at > $2
+		ORDER BY created_at DESC`
+	rows, err := sqlDB.Query(query, orgID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invites := []models.OrganizationInvite{}
+	for rows.Next() {
+		i, err := scanInvite(rows)
+		if err != nil {
+			return nil, err
+		}
+		invites = append(invites, *i)
+	}
+	return invites, rows.Err()
+}
+
+// AcceptOrganizationInvite marks the invite accepted and adds the user to the organization
+func AcceptOrganizationInvite(invite *models.OrganizationInvite, userID int) error {
+	tx, err := sqlDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE organization_invites SET accepted_at = CURRENT_TIMESTAMP WHERE id = $1 AND accepted_at IS NULL`, invite.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+
+	// An existing member keeps their role
+	if _, err := tx.Exec(`INSERT INTO organization_members (organization_id, user_id, role) VALUES ($1, $2, $3)
+		ON CONFLICT (organization_id, user_id) DO NOTHING`, invite.OrganizationID, userID, invite.Role); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func DeleteOrganizationInvite(orgID, inviteID int) error {
+	query := `DELETE FROM organization_invites WHERE id = $1 AND organization_id = $2 AND accepted_at IS NULL`
+	res, err := sqlDB.Exec(query, inviteID, orgID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
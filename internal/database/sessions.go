@@ -0,0 +1,113 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+)
+
+const sessionColumns = `id, user_id, refresh_token_hash, device_name, user_agent, ip_address, created_at, last_used_at, expires_at, revoked_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSession(row rowScanner) (*models.Session, error) {
+	s := &models.Session{}
+	err := row.Scan(
+		&s.ID,
+		&s.UserID,
+		&s.RefreshTokenHash,
+		&s.DeviceName,
+		&s.UserAgent,
+		&s.IPAddress,
+		&s.CreatedAt,
+		&s.LastUsedAt,
+		&s.ExpiresAt,
+		&s.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func CreateSession(session *models.Session) error {
+	query := `INSERT INTO sessions (user_id, refresh_token_hash, device_name, user_agent, ip_address, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at, last_used_at`
+	return sqlDB.QueryRow(query,
+		session.UserID,
+		session.RefreshTokenHash,
+		session.DeviceName,
+		session.UserAgent,
+		session.IPAddress,
+		session.ExpiresAt,
+	).Scan(&session.ID, &session.CreatedAt, &session.LastUsedAt)
+}
+
+func GetSessionByID(id int) (*models.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE id = $1`
+	return scanSession(sqlDB.QueryRow(query, id))
+}
+
+func GetSessionByRefreshTokenHash(hash string) (*models.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE refresh_token_hash = $1`
+	return scanSession(sqlDB.QueryRow(query, hash))
+}
+
+// GetActiveSessions returns the unrevoked, unexpired sessions of a user, newest first
+func GetActiveSessions(userID int) ([]models.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		ORDER BY last_used_at DESC`
+	rows, err := sqlDB.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []models.Session{}
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *s)
+	}
+	return sessions, rows.Err()
+}
+
+// RotateSessionRefreshToken swaps the refresh token of a live session and extends its expiry.
+// It matches on the old hash so two concurrent refreshes cannot both succeed.
+func RotateSessionRefreshToken(sessionID int, oldHash, newHash string, expiresAt time.Time) error {
+	query := `UPDATE sessions
+		SET refresh_token_hash = $1, expires_at = $2, last_used_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND refresh_token_hash = $4 AND revoked_at IS NULL`
+	res, err := sqlDB.Exec(query, newHash, expiresAt, sessionID, oldHash)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func RevokeSession(sessionID, userID int) error {
+	query := `UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+	res, err := sqlDB.Exec(query, sessionID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func RevokeAllSessions(userID int) error {
+	query := `UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL`
+	_, err := sqlDB.Exec(query, userID)
+	return err
+}
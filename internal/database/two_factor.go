@@ -0,0 +1,91 @@
+package database
+
+import (
+	"database/sql"
+)
+
+// SetPendingTOTPSecret stores a new secret that only takes effect once confirmed
+func SetPendingTOTPSecret(userID int, secret string) error {
+	query := `UPDATE users SET totp_secret = $1, totp_enabled_at = NULL, totp_last_step = NULL WHERE id = $2`
+	_, err := sqlDB.Exec(query, secret, userID)
+	return err
+}
+
+// GetTOTPSecret returns the user's secret and the last time step a code was accepted for
+func GetTOTPSecret(userID int) (string, int64, error) {
+	var secret sql.NullString
+	var lastStep sql.NullInt64
+	query := `SELECT totp_secret, totp_last_step FROM users WHERE id = $1`
+	if err := sqlDB.QueryRow(query, userID).Scan(&secret, &lastStep); err != nil {
+		return "", 0, err
+	}
+	if !secret.Valid || secret.String == "" {
+		return "", 0, sql.ErrNoRows
+	}
+	return secret.String, lastStep.Int64, nil
+}
+
+// UseTOTPStep records the time step of an accepted code. It reports false when
+// that step, or a later one, was already used.
+func UseTOTPStep(userID int, step int64) (bool, error) {
+	query := `UPDATE users SET totp_last_step = $1 WHERE id = $2 AND (totp_last_step IS NULL OR totp_last_step < $1)`
+	res, err := sqlDB.Exec(query, step, userID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func EnableTOTP(userID int) error {
+	query := `UPDATE users SET totp_enabled_at = CURRENT_TIMESTAMP WHERE id = $1`
+	_, err := sqlDB.Exec(query, userID)
+	return err
+}
+
+// DisableTOTP clears the secret and removes the user's recovery codes
+func DisableTOTP(userID int) error {
+	tx, err := sqlDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = NULL WHERE id = $1`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ReplaceRecoveryCodes swaps the user's recovery codes for a new set of hashes
+func ReplaceRecoveryCodes(userID int, codeHashes []string) error {
+	tx, err := sqlDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	for _, hash := range codeHashes {
+		if _, err := tx.Exec(`INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userID, hash); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// UseRecoveryCode marks an unused recovery code as used and reports whether one matched
+func UseRecoveryCode(userID int, codeHash string) (bool, error) {
+	query := `UPDATE recovery_codes SET used_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+	res, err := sqlDB.Exec(query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
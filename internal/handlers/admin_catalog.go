@@ -0,0 +1,505 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+	"gorm.io/gorm"
+)
+
+// ----------- Response Structs -----------
+
+type CatalogPaginatedResponse struct {
+	Data       interface{} `json:"data"`
+	Page       int         `json:"page"`
+	PageSize   int         `json:"page_size"`
+	TotalCount int64       `json:"total_count"`
+	TotalPages int         `json:"total_pages"`
+}
+
+type CatalogSpecies struct {
+	ID        uint       `json:"id"`
+	Name      string     `json:"name"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+type CatalogCategory struct {
+	ID        uint       `json:"id"`
+	Name      string     `json:"name"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+type CatalogRegion struct {
+	ID        uint       `json:"id"`
+	Region    string     `json:"region"`
+	Quota     float64    `json:"quota"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+type CatalogSubRegion struct {
+	ID         uint       `json:"id"`
+	RegionID   uint       `json:"region_id"`
+	RegionName string     `json:"region_name"`
+	SubRegion  string     `json:"sub_region"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	DeletedAt  *time.Time `json:"deleted_at"`
+}
+
+type CatalogSeafood struct {
+	ID            uint       `json:"id"`
+	SpeciesID     uint       `json:"species_id"`
+	SpeciesName   string     `json:"species_name"`
+	RegionID      uint       `json:"region_id"`
+	RegionName    string     `json:"region_name"`
+	SubRegionID   *uint      `json:"sub_region_id"`
+	SubRegionName *string    `json:"sub_region_name"`
+	CategoryID    uint       `json:"category_id"`
+	CategoryName  string     `json:"category_name"`
+	VolumeMT      float64    `json:"volume_mt"`
+	PriceUnit     string     `json:"price_unit"`
+	PieceWeightKg *float64   `json:"piece_weight_kg"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	DeletedAt     *time.Time `json:"deleted_at"`
+}
+
+// ----------- Entities -----------
+
+// catalogParent is a foreign key from a catalog table to another
+type catalogParent struct {
+	column string
+	table  string
+}
+
+// catalogEntity describes one catalog table for the shared list, get, delete
+// and restore handlers. Queries alias the table as t.
+type catalogEntity struct {
+	table string
+	label string
+
+	// SELECT list and joins, without WHERE
+	selectStmt string
+	// Column matched by the q search parameter
+	searchColumn string
+	orderBy      string
+
+	// Joins t to o, another row that may not live alongside t
+	uniqueJoin string
+
+	parents    []catalogParent
+	dependents []catalogParent
+
+	newList func() interface{}
+	newItem func() interface{}
+}
+
+var (
+	catalogSpecies = catalogEntity{
+		table:        "species",
+		label:        "Species",
+		selectStmt:   `SELECT t.id, t.name, t.created_at, t.updated_at, t.deleted_at FROM species t`,
+		searchColumn: "t.name",
+		orderBy:      "t.name ASC, t.id ASC",
+		uniqueJoin:   "LOWER(o.name) = LOWER(t.name)",
+		dependents:   []catalogParent{{column: "species_id", table: "seafoods"}},
+		newList:      func() interface{} { return &[]CatalogSpecies{} },
+		newItem:      func() interface{} { return &CatalogSpecies{} },
+	}
+
+	catalogCategory = catalogEntity{
+		table:        "categories",
+		label:        "Category",
+		selectStmt:   `SELECT t.id, t.name, t.created_at, t.updated_at, t.deleted_at FROM categories t`,
+		searchColumn: "t.name",
+		orderBy:      "t.name ASC, t.id ASC",
+		uniqueJoin:   "LOWER(o.name) = LOWER(t.name)",
+		dependents:   []catalogParent{{column: "category_id", table: "seafoods"}},
+		newList:      func() interface{} { return &[]CatalogCategory{} },
+		newItem:      func() interface{} { return &CatalogCategory{} },
+	}
+
+	catalogRegion = catalogEntity{
+		table:        "regions",
+		label:        "Region",
+		selectStmt:   `SELECT t.id, t.region, COALESCE(t.quota, 0) AS quota, t.created_at, t.updated_at, t.deleted_at FROM regions t`,
+		searchColumn: "t.region",
+		orderBy:      "t.region ASC, t.id ASC",
+		uniqueJoin:   "LOWER(o.region) = LOWER(t.region)",
+		dependents: []catalogParent{
+			{column: "region_id", table: "sub_regions"},
+			{column: "region_id", table: "seafoods"},
+		},
+		newList: func() interface{} { return &[]CatalogRegion{} },
+		newItem: func() interface{} { return &CatalogRegion{} },
+	}
+
+	catalogSubRegion = catalogEntity{
+		table: "sub_regions",
+		label: "Sub-region",
+		selectStmt: `SELECT t.id, t.region_id, r.region AS region_name, t.sub_region, t.created_at, t.updated_at, t.deleted_at
+			FROM sub_regions t
+			JOIN regions r ON r.id = t.region_id`,
+		searchColumn: "t.sub_region",
+		orderBy:      "r.region ASC, t.sub_region ASC, t.id ASC",
+		uniqueJoin:   "o.region_id = t.region_id AND LOWER(o.sub_region) = LOWER(t.sub_region)",
+		parents:      []catalogParent{{column: "region_id", table: "regions"}},
+		dependents:   []catalogParent{{column: "sub_region_id", table: "seafoods"}},
+		newList:      func() interface{} { return &[]CatalogSubRegion{} },
+		newItem:      func() interface{} { return &CatalogSubRegion{} },
+	}
+
+	catalogSeafood = catalogEntity{
+		table: "seafoods",
+		label: "Seafood",
+		selectStmt: `SELECT
+				t.id,
+				t.species_id, sp.name AS species_name,
+				t.region_id, r.region AS region_name,
+				t.sub_region_id, sr.sub_region AS sub_region_name,
+				t.category_id, c.name AS category_name,
+				COALESCE(t.volume_mt, 0) AS volume_mt,
+				COALESCE(t.price_unit, '') AS price_unit,
+				t.piece_weight_kg,
+				t.created_at, t.updated_at, t.deleted_at
+			FROM seafoods t
+			JOIN species sp ON sp.id = t.species_id
+			JOIN regions r ON r.id = t.region_id
+			JOIN categories c ON c.id = t.category_id
+			LEFT JOIN sub_regions sr ON sr.id = t.sub_region_id`,
+		searchColumn: "sp.name",
+		orderBy:      "sp.name ASC, r.region ASC, t.id ASC",
+		parents: []catalogParent{
+			{column: "species_id", table: "species"},
+			{column: "region_id", table: "regions"},
+			{column: "sub_region_id", table: "sub_regions"},
+			{column: "category_id", table: "categories"},
+		},
+		dependents: []catalogParent{{column: "seafood_id", table: "prices"}},
+		newList:    func() interface{} { return &[]CatalogSeafood{} },
+		newItem:    func() interface{} { return &CatalogSeafood{} },
+	}
+)
+
+// ----------- Helpers -----------
+
+func parseCatalogID(c *gin.Context) (uint, bool) {
+	var id uint
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &id); err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid id"})
+		return 0, false
+	}
+	return id, true
+}
+
+// loadCatalogItem reads one row, deleted or not, into the entity's response struct
+func loadCatalogItem(db *gorm.DB, entity catalogEntity, id uint) (interface{}, error) {
+	item := entity.newItem()
+	tx := db.Raw(entity.selectStmt+` WHERE t.id = ?`, id).Scan(item)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return item, nil
+}
+
+// liveRowExists reports whether table has a row with the id that is not soft-deleted
+func liveRowExists(db *gorm.DB, table string, id uint) (bool, error) {
+	var exists bool
+	err := db.Raw(fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE id = ? AND deleted_at IS NULL)`, table), id).
+		Scan(&exists).Error
+	return exists, err
+}
+
+// checkLiveParent writes a 400 and reports false when id is not a live row of table
+func checkLiveParent(c *gin.Context, db *gorm.DB, table, field string, id uint) bool {
+	exists, err := liveRowExists(db, table, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !exists {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s %d does not exist", field, id)})
+		return false
+	}
+	return true
+}
+
+var errCatalogConflict = errors.New("conflict")
+
+func respondCatalogItem(c *gin.Context, db *gorm.DB, entity catalogEntity, id uint, status int) {
+	item, err := loadCatalogItem(db, entity, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(status, item)
+}
+
+func auditCatalog(c *gin.Context, action string, entity catalogEntity, id uint, details string) {
+	recordAudit(c, models.AuditLogEntry{
+		Action:     action,
+		TargetType: entity.table,
+		TargetID:   fmt.Sprint(id),
+		Details:    details,
+	})
+}
+
+// ----------- Handlers -----------
+
+// adminListCatalog lists rows of a catalog table with optional q search and
+// include_deleted=true|only, paginated with page and page_size
+func adminListCatalog(db *gorm.DB, entity catalogEntity) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Parse pagination parameters
+		page := 1
+		pageSize := 50
+
+		if p := c.Query("page"); p != "" {
+			fmt.Sscanf(p, "%d", &page)
+			if page < 1 {
+				page = 1
+			}
+		}
+
+		if ps := c.Query("page_size"); ps != "" {
+			fmt.Sscanf(ps, "%d", &pageSize)
+			if pageSize < 1 || pageSize > 500 {
+				pageSize = 50
+			}
+		}
+
+		var filterConditions []string
+		var filterArgs []interface{}
+		argIndex := 1
+
+		switch c.Query("include_deleted") {
+		case "true":
+		case "only":
+			filterConditions = append(filterConditions, "t.deleted_at IS NOT NULL")
+		default:
+			filterConditions = append(filterConditions, "t.deleted_at IS NULL")
+		}
+
+		if q := strings.TrimSpace(c.Query("q")); q != "" {
+			filterConditions = append(filterConditions, fmt.Sprintf("%s ILIKE $%d", entity.searchColumn, argIndex))
+			filterArgs = append(filterArgs, "%"+q+"%")
+			argIndex++
+		}
+
+		whereClause := "TRUE"
+		if len(filterConditions) > 0 {
+			whereClause = strings.Join(filterConditions, " AND ")
+		}
+
+		// Count total
+		var totalCount int64
+		countStmt := fmt.Sprintf(`SELECT COUNT(*) FROM (%s WHERE %s) sub`, entity.selectStmt, whereClause)
+		if err := db.Raw(countStmt, filterArgs...).Scan(&totalCount).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		stmt := fmt.Sprintf(`%s WHERE %s ORDER BY %s LIMIT $%d OFFSET $%d`,
+			entity.selectStmt, whereClause, entity.orderBy, argIndex, argIndex+1)
+
+		results := entity.newList()
+		if err := db.Raw(stmt, append(filterArgs, pageSize, (page-1)*pageSize)...).Scan(results).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Calculate total pages
+		totalPages := int(totalCount) / pageSize
+		if int(totalCount)%pageSize != 0 {
+			totalPages++
+		}
+
+		c.JSON(http.StatusOK, CatalogPaginatedResponse{
+			Data:       results,
+			Page:       page,
+			PageSize:   pageSize,
+			TotalCount: totalCount,
+			TotalPages: totalPages,
+		})
+	}
+}
+
+func adminGetCatalog(db *gorm.DB, entity catalogEntity) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCatalogID(c)
+		if !ok {
+			return
+		}
+
+		item, err := loadCatalogItem(db, entity, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				c.JSON(http.StatusNotFound, gin.H{"error": entity.label + " not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, item)
+	}
+}
+
+// adminDeleteCatalog soft-deletes a row. Rows still referenced by live rows
+// elsewhere in the catalog cannot be deleted.
+func adminDeleteCatalog(db *gorm.DB, entity catalogEntity) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCatalogID(c)
+		if !ok {
+			return
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			for _, dep := range entity.dependents {
+				var count int64
+				stmt := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s = ? AND deleted_at IS NULL`, dep.table, dep.column)
+				if err := tx.Raw(stmt, id).Scan(&count).Error; err != nil {
+					return err
+				}
+				if count > 0 {
+					return fmt.Errorf("%w: %s is still used by %d %s rows", errCatalogConflict, entity.label, count, dep.table)
+				}
+			}
+
+			res := tx.Exec(fmt.Sprintf(`UPDATE %s SET deleted_at = NOW(), updated_at = NOW() WHERE id = ? AND deleted_at IS NULL`, entity.table), id)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return sql.ErrNoRows
+			}
+			return nil
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				c.JSON(http.StatusNotFound, gin.H{"error": entity.label + " not found"})
+			case errors.Is(err, errCatalogConflict):
+				c.JSON(http.StatusConflict, gin.H{"error": strings.TrimPrefix(err.Error(), errCatalogConflict.Error()+": ")})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		auditCatalog(c, models.AuditCatalogDelete, entity, id, "")
+		c.JSON(http.StatusOK, gin.H{"message": entity.label + " deleted"})
+	}
+}
+
+// adminRestoreCatalog undoes a soft delete, provided the row's parents are live
+// and no live row has taken its name in the meantime
+func adminRestoreCatalog(db *gorm.DB, entity catalogEntity) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCatalogID(c)
+		if !ok {
+			return
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var deleted bool
+			stmt := fmt.Sprintf(`SELECT deleted_at IS NOT NULL FROM %s WHERE id = ?`, entity.table)
+			res := tx.Raw(stmt, id).Scan(&deleted)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 || !deleted {
+				return sql.ErrNoRows
+			}
+
+			for _, parent := range entity.parents {
+				var missing bool
+				stmt := fmt.Sprintf(`SELECT t.%[1]s IS NOT NULL AND NOT EXISTS (
+						SELECT 1 FROM %[2]s p WHERE p.id = t.%[1]s AND p.deleted_at IS NULL)
+					FROM %[3]s t WHERE t.id = ?`, parent.column, parent.table, entity.table)
+				if err := tx.Raw(stmt, id).Scan(&missing).Error; err != nil {
+					return err
+				}
+				if missing {
+					return fmt.Errorf("%w: restore the referenced %s row first", errCatalogConflict, parent.table)
+				}
+			}
+
+			if entity.uniqueJoin != "" {
+				var count int64
+				stmt := fmt.Sprintf(`SELECT COUNT(*) FROM %[1]s t JOIN %[1]s o ON %[2]s
+					WHERE t.id = ? AND o.id <> t.id AND o.deleted_at IS NULL`, entity.table, entity.uniqueJoin)
+				if err := tx.Raw(stmt, id).Scan(&count).Error; err != nil {
+					return err
+				}
+				if count > 0 {
+					return fmt.Errorf("%w: another %s with this name exists", errCatalogConflict, strings.ToLower(entity.label))
+				}
+			}
+
+			return tx.Exec(fmt.Sprintf(`UPDATE %s SET deleted_at = NULL, updated_at = NOW() WHERE id = ?`, entity.table), id).Error
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Deleted " + strings.ToLower(entity.label) + " not found"})
+			case errors.Is(err, errCatalogConflict):
+				c.JSON(http.StatusConflict, gin.H{"error": strings.TrimPrefix(err.Error(), errCatalogConflict.Error()+": ")})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		auditCatalog(c, models.AuditCatalogRestore, entity, id, "")
+		respondCatalogItem(c, db, entity, id, http.StatusOK)
+	}
+}
+
+// ----------- Entity Handlers -----------
+
+func AdminListSpecies(db *gorm.DB) gin.HandlerFunc    { return adminListCatalog(db, catalogSpecies) }
+func AdminGetSpecies(db *gorm.DB) gin.HandlerFunc     { return adminGetCatalog(db, catalogSpecies) }
+func AdminDeleteSpecies(db *gorm.DB) gin.HandlerFunc  { return adminDeleteCatalog(db, catalogSpecies) }
+func AdminRestoreSpecies(db *gorm.DB) gin.HandlerFunc { return adminRestoreCatalog(db, catalogSpecies) }
+
+func AdminListCategories(db *gorm.DB) gin.HandlerFunc { return adminListCatalog(db, catalogCategory) }
+func AdminGetCategory(db *gorm.DB) gin.HandlerFunc    { return adminGetCatalog(db, catalogCategory) }
+func AdminDeleteCategory(db *gorm.DB) gin.HandlerFunc { return adminDeleteCatalog(db, catalogCategory) }
+func AdminRestoreCategory(db *gorm.DB) gin.HandlerFunc {
+	return adminRestoreCatalog(db, catalogCategory)
+}
+
+func AdminListRegions(db *gorm.DB) gin.HandlerFunc   { return adminListCatalog(db, catalogRegion) }
+func AdminGetRegion(db *gorm.DB) gin.HandlerFunc     { return adminGetCatalog(db, catalogRegion) }
+func AdminDeleteRegion(db *gorm.DB) gin.HandlerFunc  { return adminDeleteCatalog(db, catalogRegion) }
+func AdminRestoreRegion(db *gorm.DB) gin.HandlerFunc { return adminRestoreCatalog(db, catalogRegion) }
+
+func AdminListSubRegions(db *gorm.DB) gin.HandlerFunc { return adminListCatalog(db, catalogSubRegion) }
+func AdminGetSubRegion(db *gorm.DB) gin.HandlerFunc   { return adminGetCatalog(db, catalogSubRegion) }
+func AdminDeleteSubRegion(db *gorm.DB) gin.HandlerFunc {
+	return adminDeleteCatalog(db, catalogSubRegion)
+}
+func AdminRestoreSubRegion(db *gorm.DB) gin.HandlerFunc {
+	return adminRestoreCatalog(db, catalogSubRegion)
+}
+
+func AdminListSeafood(db *gorm.DB) gin.HandlerFunc    { return adminListCatalog(db, catalogSeafood) }
+func AdminGetSeafood(db *gorm.DB) gin.HandlerFunc     { return adminGetCatalog(db, catalogSeafood) }
+func AdminDeleteSeafood(db *gorm.DB) gin.HandlerFunc  { return adminDeleteCatalog(db, catalogSeafood) }
+func AdminRestoreSeafood(db *gorm.DB) gin.HandlerFunc { return adminRestoreCatalog(db, catalogSeafood) }
@@ -0,0 +1,581 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/utils"
+	"gorm.io/gorm"
+)
+
+// ----------- Request Structs -----------
+
+// Create requires every field except sub_region_id and piece_weight_kg.
+// Update (PATCH) changes only the fields that are present.
+
+type CatalogNameRequest struct {
+	Name *string `json:"name"`
+}
+
+type CatalogRegionRequest struct {
+	Region *string  `json:"region"`
+	Quota  *float64 `json:"quota"`
+}
+
+type CatalogSubRegionRequest struct {
+	RegionID  *uint   `json:"region_id"`
+	SubRegion *string `json:"sub_region"`
+}
+
+// CatalogSeafoodRequest treats sub_region_id 0 as clearing the sub-region
+type CatalogSeafoodRequest struct {
+	SpeciesID     *uint    `json:"species_id"`
+	RegionID      *uint    `json:"region_id"`
+	SubRegionID   *uint    `json:"sub_region_id"`
+	CategoryID    *uint    `json:"category_id"`
+	VolumeMT      *float64 `json:"volume_mt"`
+	PriceUnit     *string  `json:"price_unit"`
+	PieceWeightKg *float64 `json:"piece_weight_kg"`
+}
+
+// ----------- Validation -----------
+
+// cleanName trims a name and writes a 400 when it is empty or too long
+func cleanName(c *gin.Context, field string, name *string) (string, bool) {
+	if name == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": field + " is required"})
+		return "", false
+	}
+	trimmed := strings.TrimSpace(*name)
+	if trimmed == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": field + " cannot be empty"})
+		return "", false
+	}
+	if len([]rune(trimmed)) > 255 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": field + " must be at most 255 characters"})
+		return "", false
+	}
+	return trimmed, true
+}
+
+// checkUniqueName writes a 409 when another live row already uses the name,
+// compared case-insensitively. scope narrows the check, e.g. to one region.
+func checkUniqueName(c *gin.Context, db *gorm.DB, entity catalogEntity, column, name string, excludeID uint, scope string, scopeArgs ...interface{}) bool {
+	stmt := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE LOWER(%s) = LOWER(?) AND id <> ? AND deleted_at IS NULL`, entity.table, column)
+	args := []interface{}{name, excludeID}
+	if scope != "" {
+		stmt += " AND " + scope
+		args = append(args, scopeArgs...)
+	}
+	stmt += ")"
+
+	var taken bool
+	if err := db.Raw(stmt, args...).Scan(&taken).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if taken {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("%s %q already exists", entity.label, name)})
+		return false
+	}
+	return true
+}
+
+func checkNonNegative(c *gin.Context, field string, v *float64) bool {
+	if v != nil && *v < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": field + " cannot be negative"})
+		return false
+	}
+	return true
+}
+
+// checkLiveForUpdate writes a 404 when the row is missing or soft-deleted
+func checkLiveForUpdate(c *gin.Context, db *gorm.DB, entity catalogEntity, id uint) bool {
+	exists, err := liveRowExists(db, entity.table, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": entity.label + " not found"})
+		return false
+	}
+	return true
+}
+
+// insertCatalogRow inserts the values and returns the new row's id
+func insertCatalogRow(db *gorm.DB, table string, values map[string]interface{}) (uint, error) {
+	columns := make([]string, 0, len(values)+2)
+	placeholders := make([]string, 0, len(values)+2)
+	args := make([]interface{}, 0, len(values))
+	for column, value := range values {
+		columns = append(columns, column)
+		placeholders = append(placeholders, "?")
+		args = append(args, value)
+	}
+	columns = append(columns, "created_at", "updated_at")
+	placeholders = append(placeholders, "NOW()", "NOW()")
+
+	var id uint
+	stmt := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s) RETURNING id`,
+		table, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+	err := db.Raw(stmt, args...).Scan(&id).Error
+	return id, err
+}
+
+// updateCatalogRow sets the changed columns on a live row
+func updateCatalogRow(db *gorm.DB, table string, id uint, changes map[string]interface{}) error {
+	sets := make([]string, 0, len(changes)+1)
+	args := make([]interface{}, 0, len(changes)+1)
+	for column, value := range changes {
+		sets = append(sets, column+" = ?")
+		args = append(args, value)
+	}
+	sets = append(sets, "updated_at = NOW()")
+	args = append(args, id)
+
+	stmt := fmt.Sprintf(`UPDATE %s SET %s WHERE id = ? AND deleted_at IS NULL`, table, strings.Join(sets, ", "))
+	return db.Exec(stmt, args...).Error
+}
+
+func changedColumns(changes map[string]interface{}) string {
+	columns := make([]string, 0, len(changes))
+	for column := range changes {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return "changed=" + strings.Join(columns, ",")
+}
+
+// ----------- Species and Categories -----------
+
+func adminCreateNamed(db *gorm.DB, entity catalogEntity) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CatalogNameRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		name, ok := cleanName(c, "name", req.Name)
+		if !ok || !checkUniqueName(c, db, entity, "name", name, 0, "") {
+			return
+		}
+
+		id, err := insertCatalogRow(db, entity.table, map[string]interface{}{"name": name})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		auditCatalog(c, models.AuditCatalogCreate, entity, id, "name="+name)
+		respondCatalogItem(c, db, entity, id, http.StatusCreated)
+	}
+}
+
+func adminUpdateNamed(db *gorm.DB, entity catalogEntity) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCatalogID(c)
+		if !ok {
+			return
+		}
+
+		var req CatalogNameRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if !checkLiveForUpdate(c, db, entity, id) {
+			return
+		}
+
+		name, ok := cleanName(c, "name", req.Name)
+		if !ok || !checkUniqueName(c, db, entity, "name", name, id, "") {
+			return
+		}
+
+		if err := updateCatalogRow(db, entity.table, id, map[string]interface{}{"name": name}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		auditCatalog(c, models.AuditCatalogUpdate, entity, id, "name="+name)
+		respondCatalogItem(c, db, entity, id, http.StatusOK)
+	}
+}
+
+func AdminCreateSpecies(db *gorm.DB) gin.HandlerFunc { return adminCreateNamed(db, catalogSpecies) }
+func AdminUpdateSpecies(db *gorm.DB) gin.HandlerFunc { return adminUpdateNamed(db, catalogSpecies) }
+
+func AdminCreateCategory(db *gorm.DB) gin.HandlerFunc { return adminCreateNamed(db, catalogCategory) }
+func AdminUpdateCategory(db *gorm.DB) gin.HandlerFunc { return adminUpdateNamed(db, catalogCategory) }
+
+// ----------- Regions -----------
+
+func AdminCreateRegion(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CatalogRegionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		name, ok := cleanName(c, "region", req.Region)
+		if !ok || !checkNonNegative(c, "quota", req.Quota) {
+			return
+		}
+		if !checkUniqueName(c, db, catalogRegion, "region", name, 0, "") {
+			return
+		}
+
+		quota := 0.0
+		if req.Quota != nil {
+			quota = *req.Quota
+		}
+
+		id, err := insertCatalogRow(db, catalogRegion.table, map[string]interface{}{"region": name, "quota": quota})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		auditCatalog(c, models.AuditCatalogCreate, catalogRegion, id, "region="+name)
+		respondCatalogItem(c, db, catalogRegion, id, http.StatusCreated)
+	}
+}
+
+func AdminUpdateRegion(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCatalogID(c)
+		if !ok {
+			return
+		}
+
+		var req CatalogRegionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if !checkLiveForUpdate(c, db, catalogRegion, id) {
+			return
+		}
+
+		changes := map[string]interface{}{}
+		if req.Region != nil {
+			name, ok := cleanName(c, "region", req.Region)
+			if !ok || !checkUniqueName(c, db, catalogRegion, "region", name, id, "") {
+				return
+			}
+			changes["region"] = name
+		}
+		if req.Quota != nil {
+			if !checkNonNegative(c, "quota", req.Quota) {
+				return
+			}
+			changes["quota"] = *req.Quota
+		}
+		if len(changes) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+			return
+		}
+
+		if err := updateCatalogRow(db, catalogRegion.table, id, changes); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		auditCatalog(c, models.AuditCatalogUpdate, catalogRegion, id, changedColumns(changes))
+		respondCatalogItem(c, db, catalogRegion, id, http.StatusOK)
+	}
+}
+
+// ----------- Sub-regions -----------
+
+func AdminCreateSubRegion(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CatalogSubRegionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if req.RegionID == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "region_id is required"})
+			return
+		}
+		name, ok := cleanName(c, "sub_region", req.SubRegion)
+		if !ok || !checkLiveParent(c, db, "regions", "region_id", *req.RegionID) {
+			return
+		}
+		if !checkUniqueName(c, db, catalogSubRegion, "sub_region", name, 0, "region_id = ?", *req.RegionID) {
+			return
+		}
+
+		id, err := insertCatalogRow(db, catalogSubRegion.table, map[string]interface{}{
+			"region_id":  *req.RegionID,
+			"sub_region": name,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		auditCatalog(c, models.AuditCatalogCreate, catalogSubRegion, id, fmt.Sprintf("region_id=%d sub_region=%s", *req.RegionID, name))
+		respondCatalogItem(c, db, catalogSubRegion, id, http.StatusCreated)
+	}
+}
+
+func AdminUpdateSubRegion(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCatalogID(c)
+		if !ok {
+			return
+		}
+
+		var req CatalogSubRegionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if !checkLiveForUpdate(c, db, catalogSubRegion, id) {
+			return
+		}
+
+		var current struct {
+			RegionID  uint
+			SubRegion string
+		}
+		if err := db.Raw(`SELECT region_id, sub_region FROM sub_regions WHERE id = ?`, id).Scan(&current).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		changes := map[string]interface{}{}
+		if req.RegionID != nil && *req.RegionID != current.RegionID {
+			if !checkLiveParent(c, db, "regions", "region_id", *req.RegionID) {
+				return
+			}
+			// Seafood rows pair a region with one of its sub-regions
+			var used int64
+			if err := db.Raw(`SELECT COUNT(*) FROM seafoods WHERE sub_region_id = ? AND deleted_at IS NULL`, id).Scan(&used).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			if used > 0 {
+				c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Sub-region is used by %d seafood rows and cannot move region", used)})
+				return
+			}
+			current.RegionID = *req.RegionID
+			changes["region_id"] = *req.RegionID
+		}
+		if req.SubRegion != nil {
+			name, ok := cleanName(c, "sub_region", req.SubRegion)
+			if !ok {
+				return
+			}
+			current.SubRegion = name
+			changes["sub_region"] = name
+		}
+		if len(changes) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+			return
+		}
+		if !checkUniqueName(c, db, catalogSubRegion, "sub_region", current.SubRegion, id, "region_id = ?", current.RegionID) {
+			return
+		}
+
+		if err := updateCatalogRow(db, catalogSubRegion.table, id, changes); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		auditCatalog(c, models.AuditCatalogUpdate, catalogSubRegion, id, changedColumns(changes))
+		respondCatalogItem(c, db, catalogSubRegion, id, http.StatusOK)
+	}
+}
+
+// ----------- Seafood -----------
+
+// seafoodFields is a seafood row as it will be stored after a create or update
+type seafoodFields struct {
+	SpeciesID     uint
+	RegionID      uint
+	SubRegionID   *uint
+	CategoryID    uint
+	VolumeMT      float64
+	PriceUnit     string
+	PieceWeightKg *float64
+}
+
+// applySeafoodRequest merges req into f, writing a 400 on invalid values.
+// It returns the changed columns.
+func applySeafoodRequest(c *gin.Context, db *gorm.DB, req CatalogSeafoodRequest, f *seafoodFields) (map[string]interface{}, bool) {
+	changes := map[string]interface{}{}
+
+	if req.SpeciesID != nil {
+		if !checkLiveParent(c, db, "species", "species_id", *req.SpeciesID) {
+			return nil, false
+		}
+		f.SpeciesID = *req.SpeciesID
+		changes["species_id"] = f.SpeciesID
+	}
+	if req.CategoryID != nil {
+		if !checkLiveParent(c, db, "categories", "category_id", *req.CategoryID) {
+			return nil, false
+		}
+		f.CategoryID = *req.CategoryID
+		changes["category_id"] = f.CategoryID
+	}
+	if req.RegionID != nil {
+		if !checkLiveParent(c, db, "regions", "region_id", *req.RegionID) {
+			return nil, false
+		}
+		f.RegionID = *req.RegionID
+		changes["region_id"] = f.RegionID
+	}
+	if req.SubRegionID != nil {
+		if *req.SubRegionID == 0 {
+			f.SubRegionID = nil
+		} else {
+			if !checkLiveParent(c, db, "sub_regions", "sub_region_id", *req.SubRegionID) {
+				return nil, false
+			}
+			f.SubRegionID = req.SubRegionID
+		}
+		changes["sub_region_id"] = f.SubRegionID
+	}
+	if f.SubRegionID != nil && (req.SubRegionID != nil || req.RegionID != nil) {
+		var regionID uint
+		if err := db.Raw(`SELECT region_id FROM sub_regions WHERE id = ?`, *f.SubRegionID).Scan(&regionID).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return nil, false
+		}
+		if regionID != f.RegionID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("sub_region_id %d does not belong to region_id %d", *f.SubRegionID, f.RegionID)})
+			return nil, false
+		}
+	}
+	if req.VolumeMT != nil {
+		if !checkNonNegative(c, "volume_mt", req.VolumeMT) {
+			return nil, false
+		}
+		f.VolumeMT = *req.VolumeMT
+		changes["volume_mt"] = f.VolumeMT
+	}
+	if req.PriceUnit != nil {
+		unit, ok := utils.ParseUnit(*req.PriceUnit)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown price_unit %q", *req.PriceUnit)})
+			return nil, false
+		}
+		f.PriceUnit = string(unit)
+		changes["price_unit"] = f.PriceUnit
+	}
+	if req.PieceWeightKg != nil {
+		if *req.PieceWeightKg <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "piece_weight_kg must be positive"})
+			return nil, false
+		}
+		f.PieceWeightKg = req.PieceWeightKg
+		changes["piece_weight_kg"] = *f.PieceWeightKg
+	}
+
+	return changes, true
+}
+
+func AdminCreateSeafood(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CatalogSeafoodRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		required := []struct {
+			field   string
+			missing bool
+		}{
+			{"species_id", req.SpeciesID == nil},
+			{"region_id", req.RegionID == nil},
+			{"category_id", req.CategoryID == nil},
+			{"price_unit", req.PriceUnit == nil},
+		}
+		for _, r := range required {
+			if r.missing {
+				c.JSON(http.StatusBadRequest, gin.H{"error": r.field + " is required"})
+				return
+			}
+		}
+
+		var f seafoodFields
+		if _, ok := applySeafoodRequest(c, db, req, &f); !ok {
+			return
+		}
+
+		id, err := insertCatalogRow(db, catalogSeafood.table, map[string]interface{}{
+			"species_id":      f.SpeciesID,
+			"region_id":       f.RegionID,
+			"sub_region_id":   f.SubRegionID,
+			"category_id":     f.CategoryID,
+			"volume_mt":       f.VolumeMT,
+			"price_unit":      f.PriceUnit,
+			"piece_weight_kg": f.PieceWeightKg,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		auditCatalog(c, models.AuditCatalogCreate, catalogSeafood, id,
+			fmt.Sprintf("species_id=%d region_id=%d category_id=%d", f.SpeciesID, f.RegionID, f.CategoryID))
+		respondCatalogItem(c, db, catalogSeafood, id, http.StatusCreated)
+	}
+}
+
+func AdminUpdateSeafood(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCatalogID(c)
+		if !ok {
+			return
+		}
+
+		var req CatalogSeafoodRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if !checkLiveForUpdate(c, db, catalogSeafood, id) {
+			return
+		}
+
+		var f seafoodFields
+		err := db.Raw(`SELECT species_id, region_id, sub_region_id, category_id,
+				COALESCE(volume_mt, 0) AS volume_mt, COALESCE(price_unit, '') AS price_unit, piece_weight_kg
+			FROM seafoods WHERE id = ?`, id).Scan(&f).Error
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		changes, ok := applySeafoodRequest(c, db, req, &f)
+		if !ok {
+			return
+		}
+		if len(changes) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+			return
+		}
+
+		if err := updateCatalogRow(db, catalogSeafood.table, id, changes); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		auditCatalog(c, models.AuditCatalogUpdate, catalogSeafood, id, changedColumns(changes))
+		respondCatalogItem(c, db, catalogSeafood, id, http.StatusOK)
+	}
+}
@@ -0,0 +1,472 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+	"gorm.io/gorm"
+)
+
+var faoCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Sources a crosswalk entry can map, keyed by the source query value
+const (
+	crosswalkSourceSpecies     = "species"
+	crosswalkSourceLandingName = "landing_name"
+)
+
+// ----------- Request Structs -----------
+
+// CanonicalSpeciesRequest sets the fields that are present; an empty
+// fao_code clears it
+type CanonicalSpeciesRequest struct {
+	Name           *string `json:"name"`
+	ScientificName *string `json:"scientific_name"`
+	FAOCode        *string `json:"fao_code"`
+}
+
+// CrosswalkRequest maps exactly one of species_id or landing_name_id
+type CrosswalkRequest struct {
+	CanonicalSpeciesID uint  `json:"canonical_species_id"`
+	SpeciesID          *uint `json:"species_id"`
+	LandingNameID      *uint `json:"landing_name_id"`
+}
+
+// ----------- Response Structs -----------
+
+type CatalogCanonicalSpecies struct {
+	ID             uint       `json:"id"`
+	Name           string     `json:"name"`
+	ScientificName string     `json:"scientific_name"`
+	FAOCode        *string    `json:"fao_code"`
+	MappingCount   int        `json:"mapping_count"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	DeletedAt      *time.Time `json:"deleted_at"`
+}
+
+type CrosswalkEntry struct {
+	ID                 uint      `json:"id"`
+	CanonicalSpeciesID uint      `json:"canonical_species_id"`
+	CanonicalName      string    `json:"canonical_name"`
+	FAOCode            *string   `json:"fao_code"`
+	Source             string    `json:"source"`
+	SourceID           uint      `json:"source_id"`
+	SourceName         string    `json:"source_name"`
+	ScientificName     *string   `json:"scientific_name"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+type UnmappedSpeciesSource struct {
+	Source         string  `json:"source"`
+	SourceID       uint    `json:"source_id"`
+	SourceName     string  `json:"source_name"`
+	ScientificName *string `json:"scientific_name"`
+}
+
+// ----------- Canonical Species -----------
+
+var catalogCanonicalSpecies = catalogEntity{
+	table: "canonical_species",
+	label: "Canonical species",
+	selectStmt: `SELECT t.id, t.name, COALESCE(t.scientific_name, '') AS scientific_name, t.fao_code,
+			(SELECT COUNT(*) FROM species_crosswalk x WHERE x.canonical_species_id = t.id) AS mapping_count,
+			t.created_at, t.updated_at, t.deleted_at
+		FROM canonical_species t`,
+	searchColumn: "t.name || ' ' || COALESCE(t.scientific_name, '') || ' ' || COALESCE(t.fao_code, '')",
+	orderBy:      "t.name ASC, t.id ASC",
+	uniqueJoin:   "LOWER(o.name) = LOWER(t.name)",
+	newList:      func() interface{} { return &[]CatalogCanonicalSpecies{} },
+	newItem:      func() interface{} { return &CatalogCanonicalSpecies{} },
+}
+
+// cleanFAOCode upper-cases a code, writing a 400 when it is not three letters.
+// An empty code returns nil.
+func cleanFAOCode(c *gin.Context, code string) (*string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return nil, true
+	}
+	if !faoCodePattern.MatchString(code) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "fao_code must be a 3-letter FAO ASFIS code"})
+		return nil, false
+	}
+	return &code, true
+}
+
+// checkFAOCodeFree writes a 409 when another canonical species, deleted or
+// not, has the code. The unique index covers deleted rows too.
+func checkFAOCodeFree(c *gin.Context, db *gorm.DB, code string, excludeID uint) bool {
+	var owner struct {
+		ID      uint
+		Deleted bool
+	}
+	res := db.Raw(`SELECT id, deleted_at IS NOT NULL AS deleted FROM canonical_species WHERE fao_code = ? AND id <> ?`, code, excludeID).
+		Scan(&owner)
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": res.Error.Error()})
+		return false
+	}
+	if res.RowsAffected > 0 {
+		msg := fmt.Sprintf("FAO code %s is used by canonical species %d", code, owner.ID)
+		if owner.Deleted {
+			msg += ", which is deleted; restore it instead"
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": msg})
+		return false
+	}
+	return true
+}
+
+func AdminCreateCanonicalSpecies(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CanonicalSpeciesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		name, ok := cleanName(c, "name", req.Name)
+		if !ok || !checkUniqueName(c, db, catalogCanonicalSpecies, "name", name, 0, "") {
+			return
+		}
+
+		row := models.CanonicalSpecies{Name: truncateString(name, 150)}
+		if req.ScientificName != nil {
+			row.ScientificName = truncateString(strings.TrimSpace(*req.ScientificName), 150)
+		}
+		if req.FAOCode != nil {
+			code, ok := cleanFAOCode(c, *req.FAOCode)
+			if !ok || (code != nil && !checkFAOCodeFree(c, db, *code, 0)) {
+				return
+			}
+			row.FAOCode = code
+		}
+
+		if err := db.Create(&row).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		auditCatalog(c, models.AuditCatalogCreate, catalogCanonicalSpecies, row.ID, "name="+row.Name)
+		respondCatalogItem(c, db, catalogCanonicalSpecies, row.ID, http.StatusCreated)
+	}
+}
+
+func AdminUpdateCanonicalSpecies(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCatalogID(c)
+		if !ok {
+			return
+		}
+
+		var req CanonicalSpeciesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if !checkLiveForUpdate(c, db, catalogCanonicalSpecies, id) {
+			return
+		}
+
+		changes := map[string]interface{}{}
+		if req.Name != nil {
+			name, ok := cleanName(c, "name", req.Name)
+			if !ok || !checkUniqueName(c, db, catalogCanonicalSpecies, "name", name, id, "") {
+				return
+			}
+			changes["name"] = truncateString(name, 150)
+		}
+		if req.ScientificName != nil {
+			changes["scientific_name"] = truncateString(strings.TrimSpace(*req.ScientificName), 150)
+		}
+		if req.FAOCode != nil {
+			code, ok := cleanFAOCode(c, *req.FAOCode)
+			if !ok || (code != nil && !checkFAOCodeFree(c, db, *code, id)) {
+				return
+			}
+			changes["fao_code"] = code
+		}
+		if len(changes) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+			return
+		}
+
+		if err := updateCatalogRow(db, catalogCanonicalSpecies.table, id, changes); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		auditCatalog(c, models.AuditCatalogUpdate, catalogCanonicalSpecies, id, changedColumns(changes))
+		respondCatalogItem(c, db, catalogCanonicalSpecies, id, http.StatusOK)
+	}
+}
+
+func AdminListCanonicalSpecies(db *gorm.DB) gin.HandlerFunc {
+	return adminListCatalog(db, catalogCanonicalSpecies)
+}
+func AdminGetCanonicalSpecies(db *gorm.DB) gin.HandlerFunc {
+	return adminGetCatalog(db, catalogCanonicalSpecies)
+}
+func AdminDeleteCanonicalSpecies(db *gorm.DB) gin.HandlerFunc {
+	return adminDeleteCatalog(db, catalogCanonicalSpecies)
+}
+func AdminRestoreCanonicalSpecies(db *gorm.DB) gin.HandlerFunc {
+	return adminRestoreCatalog(db, catalogCanonicalSpecies)
+}
+
+// ----------- Crosswalk -----------
+
+const crosswalkSelect = `
+	SELECT
+		x.id,
+		x.canonical_species_id,
+		cs.name AS canonical_name,
+		cs.fao_code,
+		CASE WHEN x.species_id IS NOT NULL THEN 'species' ELSE 'landing_name' END AS source,
+		COALESCE(x.species_id, x.landing_name_id) AS source_id,
+		COALESCE(sp.name, ln.nmfs_name) AS source_name,
+		ln.scientific_name,
+		x.created_at
+	FROM species_crosswalk x
+	JOIN canonical_species cs ON cs.id = x.canonical_species_id
+	LEFT JOIN species sp ON sp.id = x.species_id
+	LEFT JOIN landing_names ln ON ln.id = x.landing_name_id`
+
+// AdminListCrosswalk lists mappings filtered by canonical_species_id, source
+// and a q search over source and canonical names
+func AdminListCrosswalk(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Parse pagination parameters
+		page := 1
+		pageSize := 50
+
+		if p := c.Query("page"); p != "" {
+			fmt.Sscanf(p, "%d", &page)
+			if page < 1 {
+				page = 1
+			}
+		}
+
+		if ps := c.Query("page_size"); ps != "" {
+			fmt.Sscanf(ps, "%d", &pageSize)
+			if pageSize < 1 || pageSize > 500 {
+				pageSize = 50
+			}
+		}
+
+		var filterConditions []string
+		var filterArgs []interface{}
+		argIndex := 1
+
+		if v := c.Query("canonical_species_id"); v != "" {
+			ids, err := parseIDList(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "canonical_species_id must be a comma-separated list of ids"})
+				return
+			}
+			if len(ids) > 0 {
+				condition, args, next := idCondition("x.canonical_species_id", ids, argIndex)
+				filterConditions = append(filterConditions, condition)
+				filterArgs = append(filterArgs, args...)
+				argIndex = next
+			}
+		}
+
+		switch c.Query("source") {
+		case "":
+		case crosswalkSourceSpecies:
+			filterConditions = append(filterConditions, "x.species_id IS NOT NULL")
+		case crosswalkSourceLandingName:
+			filterConditions = append(filterConditions, "x.landing_name_id IS NOT NULL")
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "source must be species or landing_name"})
+			return
+		}
+
+		if q := strings.TrimSpace(c.Query("q")); q != "" {
+			filterConditions = append(filterConditions, fmt.Sprintf(
+				"(COALESCE(sp.name, ln.nmfs_name) ILIKE $%[1]d OR cs.name ILIKE $%[1]d OR ln.scientific_name ILIKE $%[1]d)", argIndex))
+			filterArgs = append(filterArgs, "%"+q+"%")
+			argIndex++
+		}
+
+		whereClause := "TRUE"
+		if len(filterConditions) > 0 {
+			whereClause = strings.Join(filterConditions, " AND ")
+		}
+
+		// Count total
+		var totalCount int64
+		countStmt := fmt.Sprintf(`SELECT COUNT(*) FROM (%s WHERE %s) sub`, crosswalkSelect, whereClause)
+		if err := db.Raw(countStmt, filterArgs...).Scan(&totalCount).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		stmt := fmt.Sprintf(`%s WHERE %s ORDER BY cs.name ASC, source ASC, source_name ASC LIMIT $%d OFFSET $%d`,
+			crosswalkSelect, whereClause, argIndex, argIndex+1)
+
+		var results []CrosswalkEntry
+		if err := db.Raw(stmt, append(filterArgs, pageSize, (page-1)*pageSize)...).Scan(&results).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Calculate total pages
+		totalPages := int(totalCount) / pageSize
+		if int(totalCount)%pageSize != 0 {
+			totalPages++
+		}
+
+		c.JSON(http.StatusOK, CatalogPaginatedResponse{
+			Data:       results,
+			Page:       page,
+			PageSize:   pageSize,
+			TotalCount: totalCount,
+			TotalPages: totalPages,
+		})
+	}
+}
+
+// AdminListUnmappedSpecies lists live species and landings names that are not
+// yet mapped to a canonical species, optionally narrowed by source
+func AdminListUnmappedSpecies(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		speciesStmt := `SELECT 'species' AS source, sp.id AS source_id, sp.name AS source_name, NULL AS scientific_name
+			FROM species sp
+			WHERE sp.deleted_at IS NULL
+			  AND NOT EXISTS (SELECT 1 FROM species_crosswalk x WHERE x.species_id = sp.id)`
+		landingStmt := `SELECT 'landing_name' AS source, ln.id AS source_id, ln.nmfs_name AS source_name, ln.scientific_name
+			FROM landing_names ln
+			WHERE ln.deleted_at IS NULL
+			  AND NOT EXISTS (SELECT 1 FROM species_crosswalk x WHERE x.landing_name_id = ln.id)`
+
+		var stmt string
+		switch c.Query("source") {
+		case "":
+			stmt = speciesStmt + " UNION ALL " + landingStmt
+		case crosswalkSourceSpecies:
+			stmt = speciesStmt
+		case crosswalkSourceLandingName:
+			stmt = landingStmt
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "source must be species or landing_name"})
+			return
+		}
+
+		var results []UnmappedSpeciesSource
+		if err := db.Raw(stmt + " ORDER BY source, source_name").Scan(&results).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, results)
+	}
+}
+
+func AdminCreateCrosswalk(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CrosswalkRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if req.CanonicalSpeciesID == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "canonical_species_id is required"})
+			return
+		}
+		if (req.SpeciesID == nil) == (req.LandingNameID == nil) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Provide exactly one of species_id or landing_name_id"})
+			return
+		}
+		if !checkLiveParent(c, db, "canonical_species", "canonical_species_id", req.CanonicalSpeciesID) {
+			return
+		}
+
+		row := models.SpeciesCrosswalk{
+			CanonicalSpeciesID: req.CanonicalSpeciesID,
+			SpeciesID:          req.SpeciesID,
+			LandingNameID:      req.LandingNameID,
+		}
+
+		table, field, sourceID := "species", "species_id", req.SpeciesID
+		if req.LandingNameID != nil {
+			table, field, sourceID = "landing_names", "landing_name_id", req.LandingNameID
+		}
+		if !checkLiveParent(c, db, table, field, *sourceID) {
+			return
+		}
+
+		var existing struct {
+			ID                 uint
+			CanonicalSpeciesID uint
+		}
+		res := db.Raw(fmt.Sprintf(`SELECT id, canonical_species_id FROM species_crosswalk WHERE %s = ?`, field), *sourceID).
+			Scan(&existing)
+		if res.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": res.Error.Error()})
+			return
+		}
+		if res.RowsAffected > 0 {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":                fmt.Sprintf("%s %d is already mapped", field, *sourceID),
+				"crosswalk_id":         existing.ID,
+				"canonical_species_id": existing.CanonicalSpeciesID,
+			})
+			return
+		}
+
+		if err := db.Omit("CanonicalSpecies", "Species", "LandingName").Create(&row).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		recordAudit(c, models.AuditLogEntry{
+			Action:     models.AuditCatalogCreate,
+			TargetType: "species_crosswalk",
+			TargetID:   fmt.Sprint(row.ID),
+			Details:    fmt.Sprintf("%s=%d canonical_species_id=%d", field, *sourceID, row.CanonicalSpeciesID),
+		})
+
+		var entry CrosswalkEntry
+		if err := db.Raw(crosswalkSelect+` WHERE x.id = ?`, row.ID).Scan(&entry).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, entry)
+	}
+}
+
+// AdminDeleteCrosswalk removes a mapping; the source and canonical species are kept
+func AdminDeleteCrosswalk(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCatalogID(c)
+		if !ok {
+			return
+		}
+
+		res := db.Exec(`DELETE FROM species_crosswalk WHERE id = ?`, id)
+		if res.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": res.Error.Error()})
+			return
+		}
+		if res.RowsAffected == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Crosswalk entry not found"})
+			return
+		}
+
+		recordAudit(c, models.AuditLogEntry{
+			Action:     models.AuditCatalogDelete,
+			TargetType: "species_crosswalk",
+			TargetID:   fmt.Sprint(id),
+		})
+		c.JSON(http.StatusOK, gin.H{"message": "Crosswalk entry deleted"})
+	}
+}
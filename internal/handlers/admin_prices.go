@@ -0,0 +1,420 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Most rows accepted by one bulk insert
+const maxBulkPrices = 1000
+
+// ----------- Request Structs -----------
+
+// PriceEntryRequest is one new price. Date defaults to today and currency to EUR.
+type PriceEntryRequest struct {
+	SeafoodID uint     `json:"seafood_id"`
+	Date      string   `json:"date"`
+	Price     *float64 `json:"price"`
+	Currency  string   `json:"currency"`
+}
+
+type BulkPriceRequest struct {
+	Prices []PriceEntryRequest `json:"prices"`
+}
+
+// PriceCorrectionRequest changes the fields that are present; reason is required
+type PriceCorrectionRequest struct {
+	Date     *string  `json:"date"`
+	Price    *float64 `json:"price"`
+	Currency *string  `json:"currency"`
+	Reason   string   `json:"reason"`
+}
+
+// ----------- Response Structs -----------
+
+type PriceRecord struct {
+	ID        uint      `json:"id"`
+	SeafoodID uint      `json:"seafood_id"`
+	Date      string    `json:"date"`
+	Price     float64   `json:"price"`
+	Currency  string    `json:"currency"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type PriceRevisionRecord struct {
+	ID              uint      `json:"id"`
+	Date            string    `json:"date"`
+	Price           float64   `json:"price"`
+	Currency        string    `json:"currency"`
+	Reason          string    `json:"reason"`
+	RevisedByUserID *int      `json:"revised_by_user_id"`
+	RevisedByEmail  *string   `json:"revised_by_email,omitempty"`
+	RevisedAt       time.Time `json:"revised_at"`
+}
+
+type PriceRevisionsResponse struct {
+	Price     PriceRecord           `json:"price"`
+	Revisions []PriceRevisionRecord `json:"revisions"`
+}
+
+func newPriceRecord(p models.Price) PriceRecord {
+	return PriceRecord{
+		ID:        p.ID,
+		SeafoodID: p.SeafoodID,
+		Date:      p.Date.Format(dateLayout),
+		Price:     p.Price,
+		Currency:  p.Currency,
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+	}
+}
+
+// ----------- Validation -----------
+
+func parsePriceDate(v string) (time.Time, error) {
+	if strings.TrimSpace(v) == "" {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	t, err := time.Parse(dateLayout, strings.TrimSpace(v))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date, expected YYYY-MM-DD")
+	}
+	return t, nil
+}
+
+func validPriceValue(v float64) error {
+	// prices.price is numeric(12,2)
+	if math.IsNaN(v) || math.IsInf(v, 0) || v < 0 || v >= 1e10 {
+		return fmt.Errorf("price must be between 0 and 9999999999.99")
+	}
+	return nil
+}
+
+// buildPrice validates one entry. It does not check that the seafood exists.
+func buildPrice(req PriceEntryRequest) (models.Price, error) {
+	if req.SeafoodID == 0 {
+		return models.Price{}, fmt.Errorf("seafood_id is required")
+	}
+	if req.Price == nil {
+		return models.Price{}, fmt.Errorf("price is required")
+	}
+	if err := validPriceValue(*req.Price); err != nil {
+		return models.Price{}, err
+	}
+
+	date, err := parsePriceDate(req.Date)
+	if err != nil {
+		return models.Price{}, err
+	}
+
+	currency := "EUR"
+	if req.Currency != "" {
+		code, ok := utils.NormalizeCurrency(req.Currency)
+		if !ok {
+			return models.Price{}, fmt.Errorf("invalid currency %q", req.Currency)
+		}
+		currency = code
+	}
+
+	return models.Price{
+		SeafoodID: req.SeafoodID,
+		Date:      date,
+		Price:     math.Round(*req.Price*100) / 100,
+		Currency:  currency,
+	}, nil
+}
+
+// missingSeafood returns the ids that are not live seafood rows
+func missingSeafood(db *gorm.DB, ids []uint) ([]uint, error) {
+	var found []uint
+	if err := db.Raw(`SELECT id FROM seafoods WHERE id IN ? AND deleted_at IS NULL`, ids).Scan(&found).Error; err != nil {
+		return nil, err
+	}
+
+	live := make(map[uint]bool, len(found))
+	for _, id := range found {
+		live[id] = true
+	}
+
+	var missing []uint
+	for _, id := range ids {
+		if !live[id] {
+			missing = append(missing, id)
+			live[id] = true
+		}
+	}
+	return missing, nil
+}
+
+func auditPrice(c *gin.Context, action string, priceID uint, details string) {
+	recordAudit(c, models.AuditLogEntry{
+		Action:     action,
+		TargetType: "price",
+		TargetID:   fmt.Sprint(priceID),
+		Details:    details,
+	})
+}
+
+// ----------- Handlers -----------
+
+func AdminCreatePrice(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req PriceEntryRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		price, err := buildPrice(req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if !checkLiveParent(c, db, "seafoods", "seafood_id", price.SeafoodID) {
+			return
+		}
+
+		if err := db.Create(&price).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		auditPrice(c, models.AuditPriceCreate, price.ID,
+			fmt.Sprintf("seafood_id=%d date=%s price=%.2f %s", price.SeafoodID, price.Date.Format(dateLayout), price.Price, price.Currency))
+		c.JSON(http.StatusCreated, newPriceRecord(price))
+	}
+}
+
+// AdminBulkCreatePrices inserts every row or none. Validation errors are
+// reported per row index.
+func AdminBulkCreatePrices(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req BulkPriceRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if len(req.Prices) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "prices is required"})
+			return
+		}
+		if len(req.Prices) > maxBulkPrices {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d prices per request", maxBulkPrices)})
+			return
+		}
+
+		prices := make([]models.Price, 0, len(req.Prices))
+		seafoodIDs := make([]uint, 0, len(req.Prices))
+		rowErrors := gin.H{}
+		for i, entry := range req.Prices {
+			price, err := buildPrice(entry)
+			if err != nil {
+				rowErrors[fmt.Sprint(i)] = err.Error()
+				continue
+			}
+			prices = append(prices, price)
+			seafoodIDs = append(seafoodIDs, price.SeafoodID)
+		}
+		if len(rowErrors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid prices", "rows": rowErrors})
+			return
+		}
+
+		missing, err := missingSeafood(db, seafoodIDs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if len(missing) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown seafood_id", "seafood_ids": missing})
+			return
+		}
+
+		if err := db.CreateInBatches(&prices, 200).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		records := make([]PriceRecord, len(prices))
+		for i, p := range prices {
+			records[i] = newPriceRecord(p)
+		}
+
+		auditPrice(c, models.AuditPriceCreate, prices[0].ID,
+			fmt.Sprintf("bulk insert of %d prices, ids %d-%d", len(prices), prices[0].ID, prices[len(prices)-1].ID))
+		c.JSON(http.StatusCreated, gin.H{"count": len(records), "data": records})
+	}
+}
+
+// AdminCorrectPrice updates a price after saving its previous values as a revision
+func AdminCorrectPrice(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var id uint
+		if _, err := fmt.Sscanf(c.Param("id"), "%d", &id); err != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid price id"})
+			return
+		}
+
+		var req PriceCorrectionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		reason := strings.TrimSpace(req.Reason)
+		if reason == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "reason is required"})
+			return
+		}
+
+		changes := map[string]interface{}{}
+		if req.Price != nil {
+			if err := validPriceValue(*req.Price); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			changes["price"] = math.Round(*req.Price*100) / 100
+		}
+		if req.Currency != nil {
+			code, ok := utils.NormalizeCurrency(*req.Currency)
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid currency %q", *req.Currency)})
+				return
+			}
+			changes["currency"] = code
+		}
+		if req.Date != nil {
+			date, err := time.Parse(dateLayout, strings.TrimSpace(*req.Date))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date, expected YYYY-MM-DD"})
+				return
+			}
+			changes["date"] = date
+		}
+		if len(changes) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to correct"})
+			return
+		}
+
+		userID := c.GetInt("user_id")
+		revision := models.PriceRevision{
+			Reason:         reason,
+			RevisedByEmail: truncateString(c.GetString("email"), 255),
+		}
+		if userID != 0 {
+			revision.RevisedByUserID = &userID
+		}
+
+		var price models.Price
+		err := db.Transaction(func(tx *gorm.DB) error {
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ? AND deleted_at IS NULL", id).
+				First(&price).Error
+			if err != nil {
+				return err
+			}
+
+			revision.PriceID = price.ID
+			revision.SeafoodID = price.SeafoodID
+			revision.Date = price.Date
+			revision.Price = price.Price
+			revision.Currency = price.Currency
+			if err := tx.Create(&revision).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Model(&price).Updates(changes).Error; err != nil {
+				return err
+			}
+			return tx.First(&price, price.ID).Error
+		})
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Price not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		auditPrice(c, models.AuditPriceCorrect, price.ID,
+			fmt.Sprintf("revision_id=%d %s reason=%s", revision.ID, changedColumns(changes), truncateString(reason, 200)))
+		c.JSON(http.StatusOK, newPriceRecord(price))
+	}
+}
+
+// GetPriceRevisions returns a price with its earlier values, newest first.
+// Only admins see the email of whoever made each correction.
+func GetPriceRevisions(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var id uint
+		if _, err := fmt.Sscanf(c.Param("id"), "%d", &id); err != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid price id"})
+			return
+		}
+
+		var price models.Price
+		if err := db.Where("id = ? AND deleted_at IS NULL", id).First(&price).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Price not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		var revisions []models.PriceRevision
+		if err := db.Where("price_id = ?", id).Order("created_at DESC, id DESC").Find(&revisions).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		showEmail := isAdminCaller(c)
+		records := make([]PriceRevisionRecord, len(revisions))
+		for i, r := range revisions {
+			records[i] = PriceRevisionRecord{
+				ID:              r.ID,
+				Date:            r.Date.Format(dateLayout),
+				Price:           r.Price,
+				Currency:        r.Currency,
+				Reason:          r.Reason,
+				RevisedByUserID: r.RevisedByUserID,
+				RevisedAt:       r.CreatedAt,
+			}
+			if showEmail {
+				email := r.RevisedByEmail
+				records[i].RevisedByEmail = &email
+			}
+		}
+
+		c.JSON(http.StatusOK, PriceRevisionsResponse{
+			Price:     newPriceRecord(price),
+			Revisions: records,
+		})
+	}
+}
+
+// isAdminCaller reports whether the caller could reach the admin routes: an
+// admin signed in, or using an API key with the admin scope
+func isAdminCaller(c *gin.Context) bool {
+	if !models.HasRole(c.GetString("role"), models.RoleAdmin) {
+		return false
+	}
+	if value, ok := c.Get("api_key"); ok {
+		apiKey, ok := value.(*models.APIKey)
+		return ok && apiKey.HasScope(models.ScopeAdmin)
+	}
+	return true
+}
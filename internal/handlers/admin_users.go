@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/database"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+)
+
+type UsersPaginatedResponse struct {
+	Data       []models.User `json:"data"`
+	Page       int           `json:"page"`
+	PageSize   int           `json:"page_size"`
+	TotalCount int64         `json:"total_count"`
+	TotalPages int           `json:"total_pages"`
+}
+
+func AdminListUsers(c *gin.Context) {
+	// Parse pagination parameters
+	page := 1
+	pageSize := 20
+
+	if p := c.Query("page"); p != "" {
+		fmt.Sscanf(p, "%d", &page)
+		if page < 1 {
+			page = 1
+		}
+	}
+
+	if ps := c.Query("page_size"); ps != "" {
+		fmt.Sscanf(ps, "%d", &pageSize)
+		if pageSize < 1 {
+			pageSize = 20
+		}
+	}
+
+	users, totalCount, err := database.ListUsers(pageSize, (page-1)*pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	// Calculate total pages
+	totalPages := int(totalCount) / pageSize
+	if int(totalCount)%pageSize != 0 {
+		totalPages++
+	}
+
+	c.JSON(http.StatusOK, UsersPaginatedResponse{
+		Data:       users,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalCount: totalCount,
+		TotalPages: totalPages,
+	})
+}
+
+func AdminUpdateUserRole(c *gin.Context) {
+	var targetID int
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &targetID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+
+	var req models.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Admins cannot lock themselves out
+	if targetID == c.GetInt("user_id") && req.Role != models.RoleAdmin {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot remove your own admin role"})
+		return
+	}
+
+	if err := database.UpdateUserRole(targetID, req.Role); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
+		return
+	}
+
+	user, err := database.GetUserByID(targetID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	recordAudit(c, models.AuditLogEntry{
+		Action:     models.AuditUserRoleUpdate,
+		TargetType: "user",
+		TargetID:   fmt.Sprint(targetID),
+		Details:    "role=" + req.Role,
+	})
+
+	c.JSON(http.StatusOK, user)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/database"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/utils"
+)
+
+// apiKeyPrefix marks keys so they are recognisable in config files and secret scanners
+const apiKeyPrefix = "sfa_"
+
+func CreateAPIKey(c *gin.Context) {
+	userID := c.GetInt("user_id")
+
+	var req models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validate scopes
+	scopes := []string{}
+	seen := make(map[string]bool)
+	for _, s := range req.Scopes {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if !models.ValidScope(s) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown scope %q", s)})
+			return
+		}
+		if !seen[s] {
+			seen[s] = true
+			scopes = append(scopes, s)
+		}
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+		return
+	}
+
+	// Only members can share a key with an organization
+	if req.OrganizationID != nil {
+		if _, err := database.GetMemberRole(*req.OrganizationID, userID); err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusForbidden, gin.H{"error": "You are not a member of this organization"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+	}
+
+	// Generate key
+	secret, err := utils.GenerateRandomToken(24)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate API key"})
+		return
+	}
+	key := apiKeyPrefix + secret
+
+	apiKey := &models.APIKey{
+		UserID:         userID,
+		OrganizationID: req.OrganizationID,
+		Name:           strings.TrimSpace(req.Name),
+		Prefix:         key[:len(apiKeyPrefix)+8],
+		KeyHash:        utils.HashToken(key),
+		Scopes:         scopes,
+		ExpiresAt:      req.ExpiresAt,
+	}
+	if err := database.CreateAPIKey(apiKey); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
+		return
+	}
+
+	recordAudit(c, models.AuditLogEntry{
+		Action:     models.AuditAPIKeyCreate,
+		TargetType: "api_key",
+		TargetID:   fmt.Sprint(apiKey.ID),
+		Details:    fmt.Sprintf("name=%q scopes=%s", apiKey.Name, strings.Join(apiKey.Scopes, ",")),
+	})
+
+	// The plain key is only ever returned here
+	c.JSON(http.StatusCreated, models.CreateAPIKeyResponse{
+		Key:    key,
+		APIKey: *apiKey,
+	})
+}
+
+func ListAPIKeys(c *gin.Context) {
+	userID := c.GetInt("user_id")
+
+	keys, err := database.GetAPIKeys(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+func RevokeAPIKey(c *gin.Context) {
+	userID := c.GetInt("user_id")
+
+	var keyID int
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &keyID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key id"})
+		return
+	}
+
+	if err := database.RevokeAPIKey(keyID, userID); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke API key"})
+		return
+	}
+
+	recordAudit(c, models.AuditLogEntry{Action: models.AuditAPIKeyRevoke, TargetType: "api_key", TargetID: fmt.Sprint(keyID)})
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked"})
+}
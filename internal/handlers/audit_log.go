@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/database"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+)
+
+type AuditLogPaginatedResponse struct {
+	Data       []models.AuditLogEntry `json:"data"`
+	Page       int                    `json:"page"`
+	PageSize   int                    `json:"page_size"`
+	TotalCount int64                  `json:"total_count"`
+	TotalPages int                    `json:"total_pages"`
+}
+
+// recordAudit appends an entry to the audit log. The request's IP and user agent
+// are filled in, as is the signed-in caller when the entry names no actor.
+// A failed write never fails the request.
+func recordAudit(c *gin.Context, entry models.AuditLogEntry) {
+	if entry.ActorUserID == nil {
+		if userID := c.GetInt("user_id"); userID != 0 {
+			entry.ActorUserID = &userID
+		}
+	}
+	if entry.ActorEmail == "" {
+		entry.ActorEmail = c.GetString("email")
+	}
+	if entry.Outcome == "" {
+		entry.Outcome = models.AuditSuccess
+	}
+	entry.ActorEmail = truncateString(entry.ActorEmail, 255)
+	entry.IPAddress = c.ClientIP()
+	entry.UserAgent = c.Request.UserAgent()
+
+	if err := database.WriteAuditLog(&entry); err != nil {
+		c.Error(err)
+	}
+}
+
+// auditUser is the audit entry for an action by, and on, the given user
+func auditUser(action string, user *models.User) models.AuditLogEntry {
+	return models.AuditLogEntry{
+		Action:      action,
+		ActorUserID: &user.ID,
+		ActorEmail:  user.Email,
+		TargetType:  "user",
+		TargetID:    fmt.Sprint(user.ID),
+	}
+}
+
+// auditUserFailure is the audit entry for a failed action by, and on, the given user
+func auditUserFailure(action string, user *models.User, details string) models.AuditLogEntry {
+	entry := auditUser(action, user)
+	entry.Outcome = models.AuditFailure
+	entry.Details = details
+	return entry
+}
+
+// auditFailure is the audit entry for a failed attempt that may not map to a known user
+func auditFailure(action, email, details string) models.AuditLogEntry {
+	return models.AuditLogEntry{
+		Action:     action,
+		ActorEmail: email,
+		Outcome:    models.AuditFailure,
+		Details:    details,
+	}
+}
+
+// AdminListAuditLog lists audit entries, newest first, filtered by actor_id,
+// actor_email, action, outcome, target_type, target_id, ip, from and to
+func AdminListAuditLog(c *gin.Context) {
+	// Parse pagination parameters
+	page := 1
+	pageSize := 50
+
+	if p := c.Query("page"); p != "" {
+		fmt.Sscanf(p, "%d", &page)
+		if page < 1 {
+			page = 1
+		}
+	}
+
+	if ps := c.Query("page_size"); ps != "" {
+		fmt.Sscanf(ps, "%d", &pageSize)
+		if pageSize < 1 || pageSize > 500 {
+			pageSize = 50
+		}
+	}
+
+	filter := models.AuditLogFilter{
+		ActorEmail: strings.TrimSpace(c.Query("actor_email")),
+		Action:     strings.TrimSpace(c.Query("action")),
+		Outcome:    strings.TrimSpace(c.Query("outcome")),
+		TargetType: strings.TrimSpace(c.Query("target_type")),
+		TargetID:   strings.TrimSpace(c.Query("target_id")),
+		IPAddress:  strings.TrimSpace(c.Query("ip")),
+	}
+
+	if a := c.Query("actor_id"); a != "" {
+		var actorID int
+		if _, err := fmt.Sscanf(a, "%d", &actorID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid actor_id"})
+			return
+		}
+		filter.ActorUserID = &actorID
+	}
+
+	from, err := parseDateParam(c, "from")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	to, err := parseDateParam(c, "to")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.From = from
+	if to != nil {
+		// Include the whole "to" day
+		end := to.AddDate(0, 0, 1)
+		filter.To = &end
+	}
+
+	entries, totalCount, err := database.ListAuditLog(filter, pageSize, (page-1)*pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	// Calculate total pages
+	totalPages := int(totalCount) / pageSize
+	if int(totalCount)%pageSize != 0 {
+		totalPages++
+	}
+
+	c.JSON(http.StatusOK, AuditLogPaginatedResponse{
+		Data:       entries,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalCount: totalCount,
+		TotalPages: totalPages,
+	})
+}
@@ -4,10 +4,12 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"encoding/hex"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/config"
 	"github.com/manjunath-tintbytes/seafoodai.api/internal/database"
 	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
 	"github.com/manjunath-tintbytes/seafoodai.api/internal/utils"
@@ -22,6 +24,7 @@ func Signup(c *gin.Context) {
 
 	// Check if user exists
 	if _, err := database.GetUserByEmail(req.Email); err == nil {
+		recordAudit(c, auditFailure(models.AuditSignup, req.Email, "email already exists"))
 		c.JSON(http.StatusConflict, gin.H{"error": "Email already exists"})
 		return
 	}
@@ -42,17 +45,21 @@ func Signup(c *gin.Context) {
 		return
 	}
 
-	// Generate token
-	token, err := utils.GenerateToken(user.ID, user.Email)
+	recordAudit(c, auditUser(models.AuditSignup, user))
+
+	// Send the verification link; the account works without it unless verification is enforced
+	if err := sendVerification(user); err != nil {
+		c.Error(err)
+	}
+
+	// Start a session and generate tokens
+	response, err := issueSession(c, user, req.DeviceName)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, models.LoginResponse{
-		Token: token,
-		User:  *user,
-	})
+	c.JSON(http.StatusCreated, response)
 }
 
 func Login(c *gin.Context) {
@@ -62,10 +69,18 @@ func Login(c *gin.Context) {
 		return
 	}
 
+	// Throttle clients that keep failing
+	if ipLoginBlocked(c) {
+		recordAudit(c, auditFailure(models.AuditLogin, req.Email, "too many failed attempts from this IP"))
+		return
+	}
+
 	// Get user
 	user, err := database.GetUserByEmail(req.Email)
 	if err != nil {
 		if err == sql.ErrNoRows {
+			recordFailedLogin(c, req.Email, nil)
+			recordAudit(c, auditFailure(models.AuditLogin, req.Email, "unknown email"))
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 			return
 		}
@@ -73,23 +88,50 @@ func Login(c *gin.Context) {
 		return
 	}
 
+	if accountLocked(c, user) {
+		recordAudit(c, auditFailure(models.AuditLogin, user.Email, "account locked"))
+		return
+	}
+
 	// Check password
 	if !user.CheckPassword(req.Password) {
+		recordAudit(c, auditFailure(models.AuditLogin, user.Email, "invalid password"))
+		recordFailedLogin(c, req.Email, user)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
-	// Generate token
-	token, err := utils.GenerateToken(user.ID, user.Email)
+	// Accounts with 2FA finish logging in at POST /login/2fa, which also clears
+	// the failure count so code guesses keep counting towards a lockout
+	if user.TOTPEnabledAt != nil {
+		challenge, expiresAt, err := utils.GenerateChallengeToken(user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+			return
+		}
+		entry := auditUser(models.AuditLogin, user)
+		entry.Details = "two-factor challenge issued"
+		recordAudit(c, entry)
+		c.JSON(http.StatusOK, models.TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge,
+			ExpiresAt:         expiresAt,
+		})
+		return
+	}
+
+	recordSuccessfulLogin(c, user)
+
+	// Start a session and generate tokens
+	response, err := issueSession(c, user, req.DeviceName)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.LoginResponse{
-		Token: token,
-		User:  *user,
-	})
+	recordAudit(c, auditUser(models.AuditLogin, user))
+
+	c.JSON(http.StatusOK, response)
 }
 
 func GetProfile(c *gin.Context) {
@@ -115,6 +157,19 @@ func ForgotPassword(c *gin.Context) {
 	user, err := database.GetUserByEmail(req.Email)
 	if err != nil {
 		// Don't reveal if email exists or not for security
+		recordAudit(c, auditFailure(models.AuditPasswordResetRequest, req.Email, "unknown email"))
+		c.JSON(http.StatusOK, gin.H{"message": "If the email exists, a reset link has been sent"})
+		return
+	}
+
+	// Cap reset emails per address; the response stays the same
+	sent, err := database.CountRecentPasswordResetTokens(user.ID, time.Hour)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if sent >= config.MaxResetEmailsPerHour() {
+		recordAudit(c, auditFailure(models.AuditPasswordResetRequest, user.Email, "hourly reset email limit reached"))
 		c.JSON(http.StatusOK, gin.H{"message": "If the email exists, a reset link has been sent"})
 		return
 	}
@@ -140,6 +195,8 @@ func ForgotPassword(c *gin.Context) {
 		return
 	}
 
+	recordAudit(c, auditUser(models.AuditPasswordResetRequest, user))
+
 	c.JSON(http.StatusOK, gin.H{"message": "If the email exists, a reset link has been sent"})
 }
 
@@ -154,6 +211,7 @@ func ResetPassword(c *gin.Context) {
 	resetToken, err := database.GetPasswordResetToken(req.Token)
 	if err != nil {
 		if err == sql.ErrNoRows {
+			recordAudit(c, auditFailure(models.AuditPasswordReset, "", "unknown reset token"))
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired reset token"})
 			return
 		}
@@ -163,12 +221,14 @@ func ResetPassword(c *gin.Context) {
 
 	// Check if token is expired
 	if time.Now().After(resetToken.ExpiresAt) {
+		recordAudit(c, resetAudit(resetToken.UserID, models.AuditFailure, "expired reset token"))
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Reset token has expired"})
 		return
 	}
 
 	// Check if token is already used
 	if resetToken.Used {
+		recordAudit(c, resetAudit(resetToken.UserID, models.AuditFailure, "reused reset token"))
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Reset token has already been used"})
 		return
 	}
@@ -192,5 +252,31 @@ func ResetPassword(c *gin.Context) {
 		return
 	}
 
+	// A new password also lifts any lockout
+	if err := database.ClearLoginFailures(resetToken.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlock account"})
+		return
+	}
+
+	// Sign out every device that used the old password
+	if err := database.RevokeAllSessions(resetToken.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+		return
+	}
+
+	recordAudit(c, resetAudit(resetToken.UserID, models.AuditSuccess, ""))
+
 	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
 }
+
+// resetAudit is the audit entry for a reset attempt with a token that belongs to userID
+func resetAudit(userID int, outcome, details string) models.AuditLogEntry {
+	return models.AuditLogEntry{
+		Action:      models.AuditPasswordReset,
+		ActorUserID: &userID,
+		TargetType:  "user",
+		TargetID:    fmt.Sprint(userID),
+		Outcome:     outcome,
+		Details:     details,
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/utils"
+	"gorm.io/gorm"
+)
+
+type exchangeRateRow struct {
+	Date          time.Time
+	BaseCurrency  string
+	QuoteCurrency string
+	Rate          float64
+}
+
+// loadRateTable loads every stored rate quoted against the target currency or
+// against utils.CrossCurrency, so pairs without a quote can be crossed
+func loadRateTable(db *gorm.DB, target string) (*utils.RateTable, error) {
+	stmt := `
+		SELECT date, base_currency, quote_currency, rate
+		FROM exchange_rates
+		WHERE deleted_at IS NULL
+		  AND (base_currency IN ($1, $2) OR quote_currency IN ($1, $2))
+		ORDER BY date ASC`
+
+	var rows []exchangeRateRow
+	if err := db.Raw(stmt, target, utils.CrossCurrency).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	table := utils.NewRateTable()
+	for _, r := range rows {
+		table.Add(r.BaseCurrency, r.QuoteCurrency, r.Date, r.Rate)
+	}
+	return table, nil
+}
+
+// convertBaseline converts an optional comparison price, dropping it when no rate is known
+func convertBaseline(rates *utils.RateTable, price *float64, currency *string, date *time.Time, target string) *float64 {
+	if price == nil || currency == nil || date == nil {
+		return nil
+	}
+	converted, _, ok := rates.Convert(*price, *currency, target, *date)
+	if !ok {
+		return nil
+	}
+	return &converted
+}
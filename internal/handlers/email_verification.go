@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/config"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/database"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/utils"
+)
+
+const emailVerificationTTL = 24 * time.Hour
+
+// sendVerification creates a fresh verification token for the user and emails it
+func sendVerification(user *models.User) error {
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	if err := database.CreateEmailVerificationToken(user.ID, token, "", time.Now().Add(emailVerificationTTL)); err != nil {
+		return err
+	}
+
+	return utils.SendVerificationEmail(user.Email, token)
+}
+
+// sendEmailChangeVerification emails a verification link to the pending address
+// of an email change; following it makes that address the user's email
+func sendEmailChangeVerification(user *models.User, email string) error {
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	if err := database.CreateEmailVerificationToken(user.ID, token, email, time.Now().Add(emailVerificationTTL)); err != nil {
+		return err
+	}
+
+	return utils.SendEmailChangeVerificationEmail(email, token)
+}
+
+func VerifyEmail(c *gin.Context) {
+	var req models.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get verification token
+	verificationToken, err := database.GetEmailVerificationToken(req.Token)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired verification token"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	// Check if token is expired
+	if time.Now().After(verificationToken.ExpiresAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Verification token has expired"})
+		return
+	}
+
+	// Check if token is already used
+	if verificationToken.Used {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Verification token has already been used"})
+		return
+	}
+
+	details := ""
+	if verificationToken.Email != "" {
+		// An email change; the new address may have been taken since it was requested
+		if _, err := database.GetUserByEmail(verificationToken.Email); err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Email already exists"})
+			return
+		} else if err != sql.ErrNoRows {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+
+		if err := database.ApplyPendingEmail(verificationToken.UserID, verificationToken.Email); err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired verification token"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify email"})
+			return
+		}
+		details = "email changed to " + verificationToken.Email
+	} else if err := database.MarkUserEmailVerified(verificationToken.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify email"})
+		return
+	}
+
+	// Mark token as used
+	if err := database.MarkEmailVerificationTokenAsUsed(req.Token); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark token as used"})
+		return
+	}
+
+	recordAudit(c, models.AuditLogEntry{
+		Action:      models.AuditEmailVerify,
+		ActorUserID: &verificationToken.UserID,
+		TargetType:  "user",
+		TargetID:    fmt.Sprint(verificationToken.UserID),
+		Details:     details,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified successfully"})
+}
+
+func ResendVerification(c *gin.Context) {
+	var req models.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	const message = "If the email exists and is unverified, a verification link has been sent"
+
+	// Don't reveal if email exists or is already verified
+	user, err := database.GetUserByEmail(req.Email)
+	if err != nil || user.EmailVerifiedAt != nil {
+		c.JSON(http.StatusOK, gin.H{"message": message})
+		return
+	}
+
+	// Cap verification emails per address; the response stays the same
+	sent, err := database.CountRecentEmailVerificationTokens(user.ID, time.Hour)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if sent >= config.MaxVerificationEmailsPerHour() {
+		recordAudit(c, auditFailure(models.AuditEmailVerifyResend, user.Email, "hourly verification email limit reached"))
+		c.JSON(http.StatusOK, gin.H{"message": message})
+		return
+	}
+
+	if err := sendVerification(user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send verification email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	exportCSV  = "csv"
+	exportXLSX = "xlsx"
+
+	mimeCSV  = "text/csv"
+	mimeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// exportFormat picks the export format from the format query parameter or the
+// Accept header. An empty result means the caller wants the regular JSON response.
+func exportFormat(c *gin.Context) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(c.Query("format"))) {
+	case "csv":
+		return exportCSV, nil
+	case "xlsx", "excel":
+		return exportXLSX, nil
+	case "json":
+		return "", nil
+	case "":
+	default:
+		return "", fmt.Errorf("format must be one of json, csv, xlsx")
+	}
+
+	accept := c.GetHeader("Accept")
+	switch {
+	case strings.Contains(accept, mimeCSV):
+		return exportCSV, nil
+	case strings.Contains(accept, mimeXLSX):
+		return exportXLSX, nil
+	}
+	return "", nil
+}
+
+// maxExportRows caps the rows an export loads into memory
+const maxExportRows = 50000
+
+// maxListLimit caps the limit query parameter on list endpoints
+const maxListLimit = 1000
+
+// listLimit reads the limit query parameter, falling back to def when it is
+// missing, not a number or not positive, and capping it at maxListLimit
+func listLimit(c *gin.Context, def int) int {
+	n, err := strconv.Atoi(strings.TrimSpace(c.Query("limit")))
+	if err != nil || n <= 0 {
+		return def
+	}
+	if n > maxListLimit {
+		return maxListLimit
+	}
+	return n
+}
+
+// tableWriter writes a header row followed by data rows in the chosen export format
+type tableWriter interface {
+	WriteRow(values ...interface{}) error
+	Close() error
+}
+
+// newTableWriter sets the download headers on the response and writes the header row
+func newTableWriter(c *gin.Context, format, name string, headers []string) (tableWriter, error) {
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format(dateLayout), format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	var w tableWriter
+	switch format {
+	case exportCSV:
+		c.Header("Content-Type", mimeCSV+"; charset=utf-8")
+		c.Status(http.StatusOK)
+		w = &csvTableWriter{w: csv.NewWriter(c.Writer)}
+	case exportXLSX:
+		c.Header("Content-Type", mimeXLSX)
+		f := excelize.NewFile()
+		sheet := f.GetSheetName(0)
+		sw, err := f.NewStreamWriter(sheet)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		w = &xlsxTableWriter{c: c, f: f, sw: sw}
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+
+	values := make([]interface{}, len(headers))
+	for i, h := range headers {
+		values[i] = h
+	}
+	if err := w.WriteRow(values...); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// cellValue dereferences optional values so nil pointers become empty cells
+func cellValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case *float64:
+		if t == nil {
+			return nil
+		}
+		return *t
+	case *string:
+		if t == nil {
+			return nil
+		}
+		return *t
+	case *int:
+		if t == nil {
+			return nil
+		}
+		return *t
+	case *bool:
+		if t == nil {
+			return nil
+		}
+		return *t
+	case time.Time:
+		return t.Format(dateLayout)
+	case CustomDate:
+		return time.Time(t).Format(dateLayout)
+	}
+	return v
+}
+
+// ----------- CSV -----------
+
+type csvTableWriter struct {
+	w *csv.Writer
+}
+
+func (t *csvTableWriter) WriteRow(values ...interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		switch cv := cellValue(v).(type) {
+		case nil:
+			record[i] = ""
+		case float64:
+			record[i] = strconv.FormatFloat(cv, 'f', -1, 64)
+		case string:
+			record[i] = csvText(cv)
+		default:
+			record[i] = fmt.Sprint(cv)
+		}
+	}
+	return t.w.Write(record)
+}
+
+// csvText stops spreadsheet apps from running a text cell as a formula by
+// prefixing a quote when it starts with a formula character
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func (t *csvTableWriter) Close() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+// ----------- XLSX -----------
+
+type xlsxTableWriter struct {
+	c   *gin.Context
+	f   *excelize.File
+	sw  *excelize.StreamWriter
+	row int
+}
+
+func (t *xlsxTableWriter) WriteRow(values ...interface{}) error {
+	t.row++
+	cell, err := excelize.CoordinatesToCellName(1, t.row)
+	if err != nil {
+		return err
+	}
+	cells := make([]interface{}, len(values))
+	for i, v := range values {
+		cells[i] = cellValue(v)
+	}
+	return t.sw.SetRow(cell, cells)
+}
+
+// Close finishes the workbook and writes it to the response
+func (t *xlsxTableWriter) Close() error {
+	defer t.f.Close()
+	if err := t.sw.Flush(); err != nil {
+		return err
+	}
+	t.c.Status(http.StatusOK)
+	return t.f.Write(t.c.Writer)
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+)
+
+func TestCSVTableWriterNeutralizesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	w := &csvTableWriter{w: csv.NewWriter(&buf)}
+
+	title := "=HYPERLINK(\"http://evil.example\",\"click\")"
+	var missing *string
+	err := w.WriteRow(title, "+1 salmon", "-cod", "@SUM(A1)", "\tpadded", "\rreturn", "Atlantic salmon", "", -1.5, 3, missing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"'" + title, "'+1 salmon", "'-cod", "'@SUM(A1)", "'\tpadded", "'\rreturn", "Atlantic salmon", "", "-1.5", "3", ""}
+	if len(records) != 1 || !reflect.DeepEqual(records[0], want) {
+		t.Errorf("record = %q, want %q", records, want)
+	}
+}
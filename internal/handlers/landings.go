@@ -1,23 +1,27 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/utils"
 	"gorm.io/gorm"
 )
 
 // ----------- Response Struct -----------
 
 type LandingResponse struct {
-	Year       int     `json:"year"`
-	RegionName string  `json:"region"`
-	NMFSName   string  `json:"nmfs_name"`
-	Pounds     float64 `json:"pounds"`
-	Dollars    float64 `json:"dollars"`
-	MetricTons float64 `json:"metric_tons"`
+	Year         int      `json:"year"`
+	RegionName   string   `json:"region"`
+	NMFSName     string   `json:"nmfs_name"`
+	Pounds       float64  `json:"pounds"`
+	Dollars      float64  `json:"dollars"`
+	MetricTons   float64  `json:"metric_tons"`
+	Quantity     *float64 `json:"quantity,omitempty" gorm:"-"`
+	QuantityUnit string   `json:"quantity_unit,omitempty" gorm:"-"`
 }
 
 type LandingsPaginatedResponse struct {
@@ -28,6 +32,118 @@ type LandingsPaginatedResponse struct {
 	TotalPages int               `json:"total_pages"`
 }
 
+// ----------- Filters -----------
+
+// landingsFilter builds the WHERE clause shared by the landings endpoints from
+// the year, year_from, year_to, region, name, landing_name_id and
+// canonical_species_id query parameters. It returns an error when an id list
+// is malformed.
+func landingsFilter(c *gin.Context) (string, []interface{}, int, error) {
+	yearStr := strings.TrimSpace(c.Query("year"))
+	regionName := strings.TrimSpace(c.Query("region"))
+	nmfsName := strings.TrimSpace(c.Query("name"))
+
+	var filterConditions []string
+	var filterArgs []interface{}
+	argIndex := 1
+
+	baseWhereClause := `l.deleted_at IS NULL 
+		  AND lp.deleted_at IS NULL 
+		  AND ln.deleted_at IS NULL`
+
+	if yearStr != "" {
+		var year int
+		if _, err := fmt.Sscanf(yearStr, "%d", &year); err == nil {
+			filterConditions = append(filterConditions, fmt.Sprintf("l.year = $%d", argIndex))
+			filterArgs = append(filterArgs, year)
+			argIndex++
+		}
+	}
+
+	if yearFrom := strings.TrimSpace(c.Query("year_from")); yearFrom != "" {
+		var year int
+		if _, err := fmt.Sscanf(yearFrom, "%d", &year); err == nil {
+			filterConditions = append(filterConditions, fmt.Sprintf("l.year >= $%d", argIndex))
+			filterArgs = append(filterArgs, year)
+			argIndex++
+		}
+	}
+
+	if yearTo := strings.TrimSpace(c.Query("year_to")); yearTo != "" {
+		var year int
+		if _, err := fmt.Sscanf(yearTo, "%d", &year); err == nil {
+			filterConditions = append(filterConditions, fmt.Sprintf("l.year <= $%d", argIndex))
+			filterArgs = append(filterArgs, year)
+			argIndex++
+		}
+	}
+
+	if regionName != "" {
+		filterConditions = append(filterConditions, fmt.Sprintf("lp.region_name ILIKE $%d", argIndex))
+		filterArgs = append(filterArgs, "%"+regionName+"%")
+		argIndex++
+	}
+
+	if nmfsName != "" {
+		filterConditions = append(filterConditions, fmt.Sprintf("ln.nmfs_name ILIKE $%d", argIndex))
+		filterArgs = append(filterArgs, "%"+nmfsName+"%")
+		argIndex++
+	}
+
+	landingNameIDs, err := parseIDList(c.Query("landing_name_id"))
+	if err != nil {
+		return "", nil, 0, errors.New("landing_name_id must be a comma-separated list of ids")
+	}
+	if len(landingNameIDs) > 0 {
+		condition, args, next := idCondition("ln.id", landingNameIDs, argIndex)
+		filterConditions = append(filterConditions, condition)
+		filterArgs = append(filterArgs, args...)
+		argIndex = next
+	}
+
+	canonicalIDs, err := parseIDList(c.Query("canonical_species_id"))
+	if err != nil {
+		return "", nil, 0, errors.New("canonical_species_id must be a comma-separated list of ids")
+	}
+	if len(canonicalIDs) > 0 {
+		condition, args, next := canonicalSpeciesCondition("ln.id", "landing_name_id", canonicalIDs, argIndex)
+		filterConditions = append(filterConditions, condition)
+		filterArgs = append(filterArgs, args...)
+		argIndex = next
+	}
+
+	whereClause := baseWhereClause
+	if len(filterConditions) > 0 {
+		whereClause += " AND " + strings.Join(filterConditions, " AND ")
+	}
+
+	return whereClause, filterArgs, argIndex, nil
+}
+
+// landingsUnit reads the optional unit that landed volumes are also reported in.
+// Landings are weighed, so only mass units are accepted.
+func landingsUnit(c *gin.Context) (utils.Unit, bool) {
+	u := c.Query("unit")
+	if u == "" {
+		return "", true
+	}
+	unit, ok := utils.ParseUnit(u)
+	if !ok || unit == utils.UnitPiece {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unit must be one of kg, lb, mt"})
+		return "", false
+	}
+	return unit, true
+}
+
+// landedQuantity converts landed pounds into unit
+func landedQuantity(pounds float64, unit utils.Unit) *float64 {
+	quantity, err := utils.ConvertQuantity(pounds, utils.UnitPound, unit, nil)
+	if err != nil {
+		return nil
+	}
+	return &quantity
+}
+
 // ----------- Handler -----------
 
 func GetLandings(db *gorm.DB) gin.HandlerFunc {
@@ -50,44 +166,22 @@ func GetLandings(db *gorm.DB) gin.HandlerFunc {
 			}
 		}
 
-		// Parse filter parameters
-		yearStr := strings.TrimSpace(c.Query("year"))
-		regionName := strings.TrimSpace(c.Query("region"))
-		nmfsName := strings.TrimSpace(c.Query("name"))
-
-		// Build WHERE clause for filters
-		var filterConditions []string
-		var filterArgs []interface{}
-		argIndex := 1
-
-		baseWhereClause := `l.deleted_at IS NULL 
-			  AND lp.deleted_at IS NULL 
-			  AND ln.deleted_at IS NULL`
-
-		if yearStr != "" {
-			var year int
-			if _, err := fmt.Sscanf(yearStr, "%d", &year); err == nil {
-				filterConditions = append(filterConditions, fmt.Sprintf("l.year = $%d", argIndex))
-				filterArgs = append(filterArgs, year)
-				argIndex++
-			}
-		}
-
-		if regionName != "" {
-			filterConditions = append(filterConditions, fmt.Sprintf("lp.region_name ILIKE $%d", argIndex))
-			filterArgs = append(filterArgs, "%"+regionName+"%")
-			argIndex++
+		// Exports return every matching row instead of a single page
+		format, err := exportFormat(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
 
-		if nmfsName != "" {
-			filterConditions = append(filterConditions, fmt.Sprintf("ln.nmfs_name ILIKE $%d", argIndex))
-			filterArgs = append(filterArgs, "%"+nmfsName+"%")
-			argIndex++
+		unit, ok := landingsUnit(c)
+		if !ok {
+			return
 		}
 
-		whereClause := baseWhereClause
-		if len(filterConditions) > 0 {
-			whereClause += " AND " + strings.Join(filterConditions, " AND ")
+		whereClause, filterArgs, argIndex, err := landingsFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
 
 		// Count query with filters
@@ -114,6 +208,12 @@ func GetLandings(db *gorm.DB) gin.HandlerFunc {
 			ORDER BY l.year DESC, lp.region_name ASC, ln.nmfs_name ASC
 			LIMIT $%d OFFSET $%d`, whereClause, argIndex, argIndex+1)
 
+		if format != "" {
+			// LIMIT NULL returns all rows
+			exportLandings(c, db, format, unit, stmt, append(filterArgs, nil, 0))
+			return
+		}
+
 		// Calculate offset
 		offset := (page - 1) * pageSize
 
@@ -134,6 +234,13 @@ func GetLandings(db *gorm.DB) gin.HandlerFunc {
 			return
 		}
 
+		if unit != "" {
+			for i := range results {
+				results[i].Quantity = landedQuantity(results[i].Pounds, unit)
+				results[i].QuantityUnit = string(unit)
+			}
+		}
+
 		// Calculate total pages
 		totalPages := int(totalCount) / pageSize
 		if int(totalCount)%pageSize != 0 {
@@ -150,3 +257,210 @@ func GetLandings(db *gorm.DB) gin.HandlerFunc {
 		})
 	}
 }
+
+// exportLandings streams landings rows straight from the database into a CSV or XLSX download
+func exportLandings(c *gin.Context, db *gorm.DB, format string, unit utils.Unit, stmt string, args []interface{}) {
+	rows, err := db.Raw(stmt, args...).Rows()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	header := []string{"year", "region", "nmfs_name", "pounds", "dollars", "metric_tons"}
+	if unit != "" {
+		header = append(header, "quantity", "quantity_unit")
+	}
+
+	w, err := newTableWriter(c, format, "landings", header)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	for rows.Next() {
+		var r LandingResponse
+		if err := db.ScanRows(rows, &r); err != nil {
+			c.Error(err)
+			return
+		}
+		row := []interface{}{r.Year, r.RegionName, r.NMFSName, r.Pounds, r.Dollars, r.MetricTons}
+		if unit != "" {
+			row = append(row, landedQuantity(r.Pounds, unit), string(unit))
+		}
+		if err := w.WriteRow(row...); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+	if err := rows.Err(); err != nil {
+		c.Error(err)
+		return
+	}
+	if err := w.Close(); err != nil {
+		c.Error(err)
+	}
+}
+
+// ----------- Summary -----------
+
+type LandingSummaryRow struct {
+	Year            *int     `json:"year,omitempty"`
+	RegionName      *string  `json:"region,omitempty"`
+	NMFSName        *string  `json:"nmfs_name,omitempty"`
+	Pounds          float64  `json:"pounds"`
+	Dollars         float64  `json:"dollars"`
+	MetricTons      float64  `json:"metric_tons"`
+	DollarsPerPound *float64 `json:"dollars_per_pound"`
+	Records         int64    `json:"records"`
+	Quantity        *float64 `json:"quantity,omitempty" gorm:"-"`
+	QuantityUnit    string   `json:"quantity_unit,omitempty" gorm:"-"`
+	DollarsPerUnit  *float64 `json:"dollars_per_unit,omitempty" gorm:"-"`
+}
+
+type LandingsSummaryResponse struct {
+	GroupBy []string            `json:"group_by"`
+	Sort    string              `json:"sort"`
+	Order   string              `json:"order"`
+	Data    []LandingSummaryRow `json:"data"`
+}
+
+// Dimensions accepted by group_by, mapped to their column and output alias
+var landingSummaryDimensions = map[string][2]string{
+	"year":    {"l.year", "year"},
+	"region":  {"lp.region_name", "region_name"},
+	"species": {"ln.nmfs_name", "nmfs_name"},
+}
+
+// Metrics accepted by sort, mapped to the expression they order by
+var landingSummarySorts = map[string]string{
+	"pounds":            "pounds",
+	"dollars":           "dollars",
+	"metric_tons":       "metric_tons",
+	"dollars_per_pound": "dollars_per_pound",
+	"records":           "records",
+	"year":              "year",
+	"region":            "region_name",
+	"species":           "nmfs_name",
+}
+
+func GetLandingsSummary(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Parse grouping dimensions, keeping the order given by the caller
+		var groupBy []string
+		var selectCols []string
+		var groupCols []string
+		seen := make(map[string]bool)
+		for _, dim := range strings.Split(c.Query("group_by"), ",") {
+			dim = strings.ToLower(strings.TrimSpace(dim))
+			if dim == "" || seen[dim] {
+				continue
+			}
+			col, ok := landingSummaryDimensions[dim]
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "group_by accepts year, region and species"})
+				return
+			}
+			seen[dim] = true
+			groupBy = append(groupBy, dim)
+			selectCols = append(selectCols, fmt.Sprintf("%s AS %s", col[0], col[1]))
+			groupCols = append(groupCols, col[0])
+		}
+
+		sortKey := strings.ToLower(strings.TrimSpace(c.DefaultQuery("sort", "dollars")))
+		sortExpr, ok := landingSummarySorts[sortKey]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sort field"})
+			return
+		}
+		if _, isDim := landingSummaryDimensions[sortKey]; isDim && !seen[sortKey] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("cannot sort by %s without grouping by it", sortKey)})
+			return
+		}
+
+		order := strings.ToLower(strings.TrimSpace(c.DefaultQuery("order", "desc")))
+		if order != "asc" && order != "desc" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "order must be asc or desc"})
+			return
+		}
+
+		limit := 0
+		if l := c.Query("limit"); l != "" {
+			fmt.Sscanf(l, "%d", &limit)
+			if limit < 0 {
+				limit = 0
+			}
+		}
+
+		unit, ok := landingsUnit(c)
+		if !ok {
+			return
+		}
+
+		whereClause, filterArgs, argIndex, err := landingsFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		groupClause := ""
+		if len(groupCols) > 0 {
+			groupClause = "GROUP BY " + strings.Join(groupCols, ", ")
+		}
+
+		selectPrefix := ""
+		if len(selectCols) > 0 {
+			selectPrefix = strings.Join(selectCols, ",\n\t\t\t\t") + ","
+		}
+
+		stmt := fmt.Sprintf(`
+			SELECT
+				%s
+				SUM(COALESCE(l.pounds, 0)) AS pounds,
+				SUM(COALESCE(l.dollars, 0)) AS dollars,
+				SUM(COALESCE(l.metric_tons, 0)) AS metric_tons,
+				SUM(COALESCE(l.dollars, 0)) / NULLIF(SUM(COALESCE(l.pounds, 0)), 0) AS dollars_per_pound,
+				COUNT(*) AS records
+			FROM landings l
+			JOIN landing_ports lp ON l.landing_port_id = lp.id
+			JOIN landing_names ln ON l.landing_name_id = ln.id
+			WHERE %s
+			%s
+			ORDER BY %s %s NULLS LAST`, selectPrefix, whereClause, groupClause, sortExpr, strings.ToUpper(order))
+
+		queryArgs := filterArgs
+		if limit > 0 {
+			stmt += fmt.Sprintf("\n\t\t\tLIMIT $%d", argIndex)
+			queryArgs = append(queryArgs, limit)
+		}
+
+		var results []LandingSummaryRow
+		if err := db.Raw(stmt, queryArgs...).Scan(&results).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if unit != "" {
+			for i := range results {
+				r := &results[i]
+				r.Quantity = landedQuantity(r.Pounds, unit)
+				r.QuantityUnit = string(unit)
+				if r.Quantity != nil && *r.Quantity > 0 {
+					perUnit := r.Dollars / *r.Quantity
+					r.DollarsPerUnit = &perUnit
+				}
+			}
+		}
+
+		if groupBy == nil {
+			groupBy = []string{}
+		}
+
+		c.JSON(http.StatusOK, LandingsSummaryResponse{
+			GroupBy: groupBy,
+			Sort:    sortKey,
+			Order:   order,
+			Data:    results,
+		})
+	}
+}
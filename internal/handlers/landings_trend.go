@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/utils"
+	"gorm.io/gorm"
+)
+
+// ----------- Response Struct -----------
+
+type LandingTrendPoint struct {
+	Year              int      `json:"year"`
+	Pounds            float64  `json:"pounds"`
+	Dollars           float64  `json:"dollars"`
+	MetricTons        float64  `json:"metric_tons"`
+	DollarsPerPound   *float64 `json:"dollars_per_pound"`
+	PoundsYoY         *float64 `json:"pounds_yoy"`
+	DollarsYoY        *float64 `json:"dollars_yoy"`
+	PoundsRollingAvg  float64  `json:"pounds_rolling_avg"`
+	DollarsRollingAvg float64  `json:"dollars_rolling_avg"`
+}
+
+type LandingTrendResponse struct {
+	Species       string              `json:"species"`
+	Region        *string             `json:"region"`
+	RollingWindow int                 `json:"rolling_window"`
+	PoundsCAGR5Y  *float64            `json:"pounds_cagr_5y"`
+	DollarsCAGR5Y *float64            `json:"dollars_cagr_5y"`
+	Data          []LandingTrendPoint `json:"data"`
+}
+
+type landingYearTotal struct {
+	Year       int
+	Pounds     float64
+	Dollars    float64
+	MetricTons float64
+}
+
+const cagrYears = 5
+
+// maxTrendWindow caps the rolling-average window in years
+const maxTrendWindow = 50
+
+// ----------- Handler -----------
+
+func GetLandingsTrend(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		nmfsName := strings.TrimSpace(c.Query("species"))
+		scientificName := strings.TrimSpace(c.Query("scientific_name"))
+		regionName := strings.TrimSpace(c.Query("region"))
+
+		window := 3
+		if w := c.Query("window"); w != "" {
+			fmt.Sscanf(w, "%d", &window)
+			if window < 1 {
+				window = 3
+			}
+			if window > maxTrendWindow {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("window must be at most %d years", maxTrendWindow)})
+				return
+			}
+		}
+
+		var filterConditions []string
+		var filterArgs []interface{}
+		argIndex := 1
+
+		baseWhereClause := `l.deleted_at IS NULL
+			  AND lp.deleted_at IS NULL
+			  AND ln.deleted_at IS NULL`
+
+		// Species is matched exactly so related species are not summed together
+		species := nmfsName
+		switch {
+		case nmfsName != "":
+			filterConditions = append(filterConditions, fmt.Sprintf("ln.nmfs_name ILIKE $%d", argIndex))
+			filterArgs = append(filterArgs, nmfsName)
+			argIndex++
+		case scientificName != "":
+			species = scientificName
+			filterConditions = append(filterConditions, fmt.Sprintf("ln.scientific_name ILIKE $%d", argIndex))
+			filterArgs = append(filterArgs, scientificName)
+			argIndex++
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "species or scientific_name is required"})
+			return
+		}
+
+		var region *string
+		if regionName != "" {
+			region = &regionName
+			filterConditions = append(filterConditions, fmt.Sprintf("lp.region_name ILIKE $%d", argIndex))
+			filterArgs = append(filterArgs, regionName)
+			argIndex++
+		}
+
+		whereClause := baseWhereClause + " AND " + strings.Join(filterConditions, " AND ")
+
+		stmt := fmt.Sprintf(`
+			SELECT
+				l.year,
+				SUM(COALESCE(l.pounds, 0)) AS pounds,
+				SUM(COALESCE(l.dollars, 0)) AS dollars,
+				SUM(COALESCE(l.metric_tons, 0)) AS metric_tons
+			FROM landings l
+			JOIN landing_ports lp ON l.landing_port_id = lp.id
+			JOIN landing_names ln ON l.landing_name_id = ln.id
+			WHERE %s
+			GROUP BY l.year
+			ORDER BY l.year ASC`, whereClause)
+
+		var totals []landingYearTotal
+		if err := db.Raw(stmt, filterArgs...).Scan(&totals).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		byYear := make(map[int]landingYearTotal, len(totals))
+		for _, t := range totals {
+			byYear[t.Year] = t
+		}
+
+		points := make([]LandingTrendPoint, len(totals))
+		for i, t := range totals {
+			point := LandingTrendPoint{
+				Year:       t.Year,
+				Pounds:     t.Pounds,
+				Dollars:    t.Dollars,
+				MetricTons: t.MetricTons,
+			}
+			if t.Pounds > 0 {
+				dpp := t.Dollars / t.Pounds
+				point.DollarsPerPound = &dpp
+			}
+
+			// Year-over-year change only against the immediately preceding year
+			if prev, ok := byYear[t.Year-1]; ok {
+				point.PoundsYoY = utils.CalculateChange(t.Pounds, &prev.Pounds)
+				point.DollarsYoY = utils.CalculateChange(t.Dollars, &prev.Dollars)
+			}
+
+			// Rolling average over the years present in the trailing window
+			var sumPounds, sumDollars float64
+			var n int
+			for y := t.Year - window + 1; y <= t.Year; y++ {
+				if w, ok := byYear[y]; ok {
+					sumPounds += w.Pounds
+					sumDollars += w.Dollars
+					n++
+				}
+			}
+			point.PoundsRollingAvg = sumPounds / float64(n)
+			point.DollarsRollingAvg = sumDollars / float64(n)
+
+			points[i] = point
+		}
+
+		response := LandingTrendResponse{
+			Species:       species,
+			Region:        region,
+			RollingWindow: window,
+			Data:          points,
+		}
+
+		// Five-year CAGR measured from the latest year back
+		if len(totals) > 0 {
+			latest := totals[len(totals)-1]
+			if past, ok := byYear[latest.Year-cagrYears]; ok {
+				response.PoundsCAGR5Y = utils.CalculateCAGR(latest.Pounds, &past.Pounds, cagrYears)
+				response.DollarsCAGR5Y = utils.CalculateCAGR(latest.Dollars, &past.Dollars, cagrYears)
+			}
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
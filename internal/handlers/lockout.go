@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/config"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/database"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/utils"
+)
+
+// ipLoginBlocked writes a 429 and reports true when the client IP has used up
+// its failed logins for the current window
+func ipLoginBlocked(c *gin.Context) bool {
+	window := config.LoginAttemptWindow()
+	count, err := database.CountRecentFailedLoginsByIP(c.ClientIP(), window)
+	if err != nil {
+		c.Error(err)
+		return false
+	}
+	if count < config.MaxFailedLoginsPerIP() {
+		return false
+	}
+
+	c.Header("Retry-After", strconv.Itoa(int(window.Seconds())))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many failed login attempts, please try again later"})
+	return true
+}
+
+// accountLocked writes a 423 and reports true while the user's account is locked
+func accountLocked(c *gin.Context, user *models.User) bool {
+	if user.LockedUntil == nil || !time.Now().Before(*user.LockedUntil) {
+		return false
+	}
+
+	retryAfter := int(math.Ceil(time.Until(*user.LockedUntil).Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusLocked, gin.H{
+		"error":        "Account is temporarily locked after too many failed login attempts",
+		"locked_until": user.LockedUntil,
+	})
+	return true
+}
+
+// recordFailedLogin logs a failed attempt and locks the account, mailing an
+// unlock link, once it reaches the configured number of consecutive failures.
+// user is nil when the email does not belong to an account.
+func recordFailedLogin(c *gin.Context, email string, user *models.User) {
+	if err := database.RecordLoginAttempt(loginAttemptEmail(email), c.ClientIP(), false); err != nil {
+		c.Error(err)
+	}
+	if user == nil {
+		return
+	}
+
+	count, err := database.IncrementFailedLogins(user.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if count < config.MaxFailedLogins() {
+		return
+	}
+
+	lockedUntil := time.Now().Add(config.LoginLockoutDuration())
+	if err := database.LockUser(user.ID, lockedUntil); err != nil {
+		c.Error(err)
+		return
+	}
+
+	entry := auditUser(models.AuditAccountLock, user)
+	entry.Details = fmt.Sprintf("locked until %s after %d failed attempts", lockedUntil.UTC().Format(time.RFC3339), count)
+	recordAudit(c, entry)
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if err := database.CreateUnlockToken(user.ID, token, lockedUntil); err != nil {
+		c.Error(err)
+		return
+	}
+	if err := utils.SendAccountUnlockEmail(user.Email, token, lockedUntil); err != nil {
+		c.Error(err)
+	}
+}
+
+// recordSuccessfulLogin logs the attempt and clears the user's failure count
+func recordSuccessfulLogin(c *gin.Context, user *models.User) {
+	if err := database.RecordLoginAttempt(loginAttemptEmail(user.Email), c.ClientIP(), true); err != nil {
+		c.Error(err)
+	}
+	if err := database.ClearLoginFailures(user.ID); err != nil {
+		c.Error(err)
+	}
+}
+
+func loginAttemptEmail(email string) string {
+	return truncateString(strings.ToLower(strings.TrimSpace(email)), 255)
+}
+
+// UnlockAccount lifts a lockout using the token mailed when the account was locked
+func UnlockAccount(c *gin.Context) {
+	var req models.UnlockAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get unlock token
+	unlockToken, err := database.GetUnlockToken(req.Token)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired unlock token"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	// Check if token is expired
+	if time.Now().After(unlockToken.ExpiresAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unlock token has expired"})
+		return
+	}
+
+	// Check if token is already used
+	if unlockToken.Used {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unlock token has already been used"})
+		return
+	}
+
+	if err := database.ClearLoginFailures(unlockToken.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlock account"})
+		return
+	}
+
+	// Mark token as used
+	if err := database.MarkUnlockTokenAsUsed(req.Token); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark token as used"})
+		return
+	}
+
+	recordAudit(c, models.AuditLogEntry{
+		Action:      models.AuditAccountUnlock,
+		ActorUserID: &unlockToken.UserID,
+		TargetType:  "user",
+		TargetID:    fmt.Sprint(unlockToken.UserID),
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account unlocked successfully"})
+}
@@ -12,25 +12,50 @@ import (
 )
 
 type MarketPrice struct {
-	SpeciesSKU  string   `json:"species_sku"`
-	Origin      string   `json:"origin"`
-	Price       float64  `json:"price"`
-	PriceUnit   string   `json:"price_unit"`
-	WeeklyTrend *float64 `json:"weekly_trend"`
-	YoY         *float64 `json:"yoy"`
+	SpeciesSKU    string  `json:"species_sku"`
+	Origin        string  `json:"origin"`
+	Category      *string `json:"category,omitempty"`
+	SubRegion     *string `json:"sub_region,omitempty"`
+	Price         float64 `json:"price"`
+	PriceUnit     string  `json:"price_unit"`
+	UnitConverted *bool   `json:"unit_converted,omitempty"`
+	UnitError     *string `json:"unit_conversion_error,omitempty"`
+	Currency      string  `json:"currency"`
+	RateDate      *string `json:"rate_date,omitempty"`
+	// CurrencyConverted is set when a currency was requested; rows without an
+	// exchange rate keep their own price and currency and explain why
+	CurrencyConverted *bool    `json:"currency_converted,omitempty"`
+	CurrencyError     *string  `json:"currency_error,omitempty"`
+	WeeklyTrend       *float64 `json:"weekly_trend"`
+	YoY               *float64 `json:"yoy"`
+	RecentSignals     *int64   `json:"recent_signals,omitempty"`
 }
 
 type MarketPriceResult struct {
-	ID           uint      `json:"id"`
-	Price        float64   `json:"price"`
-	Date         time.Time `json:"date"`
-	PriceUnit    string    `json:"price_unit"`
-	SpeciesName  string    `json:"species_name"`
-	RegionName   string    `json:"region_name"`
-	SpeciesID    uint      `json:"species_id"`
-	RegionID     uint      `json:"region_id"`
-	WeekAgoPrice *float64  `json:"week_ago_price"`
-	YearAgoPrice *float64  `json:"year_ago_price"`
+	ID              uint       `json:"id"`
+	Price           float64    `json:"price"`
+	Currency        string     `json:"currency"`
+	Date            time.Time  `json:"date"`
+	PriceUnit       string     `json:"price_unit"`
+	PieceWeightKg   *float64   `json:"piece_weight_kg"`
+	SpeciesName     string     `json:"species_name"`
+	RegionName      string     `json:"region_name"`
+	SpeciesID       uint       `json:"species_id"`
+	RegionID        uint       `json:"region_id"`
+	CategoryID      uint       `json:"category_id"`
+	CategoryName    *string    `json:"category_name"`
+	SubRegionID     *uint      `json:"sub_region_id"`
+	SubRegionName   *string    `json:"sub_region_name"`
+	WeekAgoPrice    *float64   `json:"week_ago_price"`
+	WeekAgoCurrency *string    `json:"week_ago_currency"`
+	WeekAgoDate     *time.Time `json:"week_ago_date"`
+	WeekAgoUnit     *string    `json:"week_ago_price_unit" gorm:"column:week_ago_price_unit"`
+	WeekAgoPieceKg  *float64   `json:"week_ago_piece_weight_kg" gorm:"column:week_ago_piece_weight_kg"`
+	YearAgoPrice    *float64   `json:"year_ago_price"`
+	YearAgoCurrency *string    `json:"year_ago_currency"`
+	YearAgoDate     *time.Time `json:"year_ago_date"`
+	YearAgoUnit     *string    `json:"year_ago_price_unit" gorm:"column:year_ago_price_unit"`
+	YearAgoPieceKg  *float64   `json:"year_ago_piece_weight_kg" gorm:"column:year_ago_piece_weight_kg"`
 }
 
 type PaginatedResponse struct {
@@ -63,9 +88,86 @@ func GetMarketPricesOptimized(db *gorm.DB) gin.HandlerFunc {
 			}
 		}
 
+		// Exports return every matching row instead of a single page
+		format, err := exportFormat(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		// Parse filter parameters
 		speciesName := strings.TrimSpace(c.Query("species"))
 		regionName := strings.TrimSpace(c.Query("region"))
+		speciesIDs, err := parseIDList(c.Query("species_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "species_id must be a comma-separated list of ids"})
+			return
+		}
+		canonicalIDs, err := parseIDList(c.Query("canonical_species_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "canonical_species_id must be a comma-separated list of ids"})
+			return
+		}
+		categoryName := strings.TrimSpace(c.Query("category"))
+		categoryIDs, err := parseIDList(c.Query("category_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "category_id must be a comma-separated list of ids"})
+			return
+		}
+		// Seeded sub-regions hold the retail size/weight range, e.g. "2-3 kg"
+		subRegionName := strings.TrimSpace(c.Query("sub_region"))
+		subRegionIDs, err := parseIDList(c.Query("sub_region_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "sub_region_id must be a comma-separated list of ids"})
+			return
+		}
+
+		// Prices are grouped by species and region, and optionally by category and sub-region
+		groupByCategory, groupBySubRegion := false, false
+		for _, dim := range strings.Split(c.Query("group_by"), ",") {
+			switch strings.ToLower(strings.TrimSpace(dim)) {
+			case "":
+			case "category":
+				groupByCategory = true
+			case "sub_region":
+				groupBySubRegion = true
+			default:
+				c.JSON(http.StatusBadRequest, gin.H{"error": "group_by must be a comma-separated list of category, sub_region"})
+				return
+			}
+		}
+
+		// Optional target currency for conversion
+		var targetCurrency string
+		if cur := c.Query("currency"); cur != "" {
+			code, ok := utils.NormalizeCurrency(cur)
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "currency must be a 3-letter ISO 4217 code"})
+				return
+			}
+			targetCurrency = code
+		}
+
+		// Optional count of recently published signals linked to each species
+		includeSignals := c.Query("include_signals") == "true"
+		signalDays := 30
+		if d := c.Query("signal_days"); d != "" {
+			fmt.Sscanf(d, "%d", &signalDays)
+			if signalDays < 1 {
+				signalDays = 30
+			}
+		}
+
+		// Optional target unit of measure for conversion
+		var targetUnit utils.Unit
+		if u := c.Query("unit"); u != "" {
+			unit, ok := utils.ParseUnit(u)
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "unit must be one of kg, lb, mt, unit"})
+				return
+			}
+			targetUnit = unit
+		}
 
 		// Build WHERE clause for filters
 		var filterConditions []string
@@ -89,6 +191,79 @@ func GetMarketPricesOptimized(db *gorm.DB) gin.HandlerFunc {
 			argIndex++
 		}
 
+		if len(speciesIDs) > 0 {
+			condition, args, next := idCondition("s.species_id", speciesIDs, argIndex)
+			filterConditions = append(filterConditions, condition)
+			filterArgs = append(filterArgs, args...)
+			argIndex = next
+		}
+
+		if len(canonicalIDs) > 0 {
+			condition, args, next := canonicalSpeciesCondition("s.species_id", "species_id", canonicalIDs, argIndex)
+			filterConditions = append(filterConditions, condition)
+			filterArgs = append(filterArgs, args...)
+			argIndex = next
+		}
+
+		// Category and sub-region filters also narrow the week-ago and year-ago
+		// baselines, so trends compare the same product
+		var productConditions []string
+
+		if categoryName != "" {
+			productConditions = append(productConditions, fmt.Sprintf("c.name ILIKE $%d", argIndex))
+			filterArgs = append(filterArgs, "%"+categoryName+"%")
+			argIndex++
+		}
+
+		if len(categoryIDs) > 0 {
+			condition, args, next := idCondition("s.category_id", categoryIDs, argIndex)
+			productConditions = append(productConditions, condition)
+			filterArgs = append(filterArgs, args...)
+			argIndex = next
+		}
+
+		if subRegionName != "" {
+			productConditions = append(productConditions, fmt.Sprintf("sr.sub_region ILIKE $%d", argIndex))
+			filterArgs = append(filterArgs, "%"+subRegionName+"%")
+			argIndex++
+		}
+
+		if len(subRegionIDs) > 0 {
+			condition, args, next := idCondition("s.sub_region_id", subRegionIDs, argIndex)
+			productConditions = append(productConditions, condition)
+			filterArgs = append(filterArgs, args...)
+			argIndex = next
+		}
+
+		filterConditions = append(filterConditions, productConditions...)
+		baselineFilter := ""
+		if len(productConditions) > 0 {
+			baselineFilter = "AND " + strings.Join(productConditions, " AND ")
+		}
+
+		// Columns of seafoods that identify one market price row
+		groupColumns := []string{"species_id", "region_id"}
+		if groupByCategory {
+			groupColumns = append(groupColumns, "category_id")
+		}
+		if groupBySubRegion {
+			groupColumns = append(groupColumns, "sub_region_id")
+		}
+		partition := "s." + strings.Join(groupColumns, ", s.")
+		baselineJoin := make([]string, len(groupColumns))
+		resultJoin := make([]string, len(groupColumns))
+		for i, col := range groupColumns {
+			op := "="
+			if col == "sub_region_id" {
+				// Rows without a sub-region still form a group
+				op = "IS NOT DISTINCT FROM"
+			}
+			baselineJoin[i] = fmt.Sprintf("s.%[1]s %[2]s ll.%[1]s", col, op)
+			resultJoin[i] = fmt.Sprintf("ll.%[1]s %[2]s %%[1]s.%[1]s", col, op)
+		}
+		wapJoin := fmt.Sprintf(strings.Join(resultJoin, " AND "), "wap")
+		yapJoin := fmt.Sprintf(strings.Join(resultJoin, " AND "), "yap")
+
 		whereClause := baseWhereClause
 		if len(filterConditions) > 0 {
 			whereClause += " AND " + strings.Join(filterConditions, " AND ")
@@ -96,85 +271,130 @@ func GetMarketPricesOptimized(db *gorm.DB) gin.HandlerFunc {
 
 		// Count query with filters
 		countStmt := fmt.Sprintf(`
-			SELECT COUNT(DISTINCT (s.species_id, s.region_id))
-			FROM prices p
-			JOIN seafoods s ON p.seafood_id = s.id
-			JOIN species sp ON s.species_id = sp.id
-			JOIN regions r ON s.region_id = r.id
-			WHERE %s`, whereClause)
+			SELECT COUNT(*) FROM (
+				SELECT DISTINCT %s
+				FROM prices p
+				JOIN seafoods s ON p.seafood_id = s.id
+				JOIN species sp ON s.species_id = sp.id
+				JOIN regions r ON s.region_id = r.id
+				LEFT JOIN categories c ON s.category_id = c.id
+				LEFT JOIN sub_regions sr ON s.sub_region_id = sr.id
+				WHERE %s
+			) groups`, partition, whereClause)
 
 		// Main query with filters
 		stmt := fmt.Sprintf(`
 			WITH latest_per_species_region AS (
-				SELECT DISTINCT ON (s.species_id, s.region_id)
+				SELECT DISTINCT ON (%[1]s)
 					p.id,
 					p.price,
+					p.currency,
 					p.date,
 					s.price_unit,
+					s.piece_weight_kg,
 					sp.name as species_name,
 					r.region as region_name,
+					c.name as category_name,
+					sr.sub_region as sub_region_name,
 					s.species_id,
-					s.region_id
+					s.region_id,
+					s.category_id,
+					s.sub_region_id
 				FROM prices p
 				JOIN seafoods s ON p.seafood_id = s.id
 				JOIN species sp ON s.species_id = sp.id
 				JOIN regions r ON s.region_id = r.id
-				WHERE %s
-				ORDER BY s.species_id, s.region_id, p.date DESC
+				LEFT JOIN categories c ON s.category_id = c.id
+				LEFT JOIN sub_regions sr ON s.sub_region_id = sr.id
+				WHERE %[2]s
+				ORDER BY %[1]s, p.date DESC
 			),
 			latest_limited AS (
 				SELECT * FROM latest_per_species_region
 				ORDER BY date DESC
-				LIMIT $%d OFFSET $%d
+				LIMIT $%[3]d OFFSET $%[4]d
 			),
 			week_ago_prices AS (
-				SELECT DISTINCT ON (s.species_id, s.region_id)
+				SELECT DISTINCT ON (%[1]s)
 					s.species_id,
 					s.region_id,
-					p.price as week_ago_price
+					s.category_id,
+					s.sub_region_id,
+					p.price as week_ago_price,
+					p.currency as week_ago_currency,
+					p.date as week_ago_date,
+					s.price_unit as week_ago_price_unit,
+					s.piece_weight_kg as week_ago_piece_weight_kg
 				FROM latest_limited ll
-				JOIN seafoods s ON s.species_id = ll.species_id AND s.region_id = ll.region_id
+				JOIN seafoods s ON %[5]s
 				JOIN prices p ON p.seafood_id = s.id
+				LEFT JOIN categories c ON s.category_id = c.id
+				LEFT JOIN sub_regions sr ON s.sub_region_id = sr.id
 				WHERE p.date < ll.date - INTERVAL '6 days'
 				  AND p.deleted_at IS NULL
 				  AND s.deleted_at IS NULL
-				ORDER BY s.species_id, s.region_id, p.date DESC
+				  %[8]s
+				ORDER BY %[1]s, p.date DESC
 			),
 			year_ago_prices AS (
-				SELECT DISTINCT ON (s.species_id, s.region_id)
+				SELECT DISTINCT ON (%[1]s)
 					s.species_id,
 					s.region_id,
-					p.price as year_ago_price
+					s.category_id,
+					s.sub_region_id,
+					p.price as year_ago_price,
+					p.currency as year_ago_currency,
+					p.date as year_ago_date,
+					s.price_unit as year_ago_price_unit,
+					s.piece_weight_kg as year_ago_piece_weight_kg
 				FROM latest_limited ll
-				JOIN seafoods s ON s.species_id = ll.species_id AND s.region_id = ll.region_id
+				JOIN seafoods s ON %[5]s
 				JOIN prices p ON p.seafood_id = s.id
+				LEFT JOIN categories c ON s.category_id = c.id
+				LEFT JOIN sub_regions sr ON s.sub_region_id = sr.id
 				WHERE p.date >= make_date(EXTRACT(YEAR FROM ll.date)::int - 1, 1, 1)
 				  AND p.date < make_date(EXTRACT(YEAR FROM ll.date)::int, 1, 1)
 				  AND p.deleted_at IS NULL
 				  AND s.deleted_at IS NULL
-				ORDER BY s.species_id, s.region_id, p.date ASC
+				  %[8]s
+				ORDER BY %[1]s, p.date ASC
 			)
 			SELECT
 				ll.*,
 				wap.week_ago_price,
-				yap.year_ago_price
+				wap.week_ago_currency,
+				wap.week_ago_date,
+				wap.week_ago_price_unit,
+				wap.week_ago_piece_weight_kg,
+				yap.year_ago_price,
+				yap.year_ago_currency,
+				yap.year_ago_date,
+				yap.year_ago_price_unit,
+				yap.year_ago_piece_weight_kg
 			FROM latest_limited ll
-			LEFT JOIN week_ago_prices wap ON ll.species_id = wap.species_id AND ll.region_id = wap.region_id
-			LEFT JOIN year_ago_prices yap ON ll.species_id = yap.species_id AND ll.region_id = yap.region_id
-			ORDER BY ll.date DESC`, whereClause, argIndex, argIndex+1)
+			LEFT JOIN week_ago_prices wap ON %[6]s
+			LEFT JOIN year_ago_prices yap ON %[7]s
+			ORDER BY ll.date DESC`, partition, whereClause, argIndex, argIndex+1,
+			strings.Join(baselineJoin, " AND "), wapJoin, yapJoin, baselineFilter)
 
 		// Calculate offset
 		offset := (page - 1) * pageSize
 
-		// Prepare arguments for queries
-		queryArgs := append(filterArgs, pageSize, offset)
+		// Prepare arguments for queries (exports return up to maxExportRows rows)
+		limitArg := pageSize
+		if format != "" {
+			limitArg, offset = maxExportRows, 0
+		}
+		queryArgs := append(filterArgs, limitArg, offset)
 
 		// Get total count
 		var totalCount int64
-		err := db.Raw(countStmt, filterArgs...).Scan(&totalCount).Error
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
+		if format == "" {
+			err = db.Raw(countStmt, filterArgs...).Scan(&totalCount).Error
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
 		}
 
 		// Get paginated results
@@ -185,17 +405,109 @@ func GetMarketPricesOptimized(db *gorm.DB) gin.HandlerFunc {
 			return
 		}
 
+		// Load exchange rates when a conversion was requested
+		var rates *utils.RateTable
+		if targetCurrency != "" {
+			rates, err = loadRateTable(db, targetCurrency)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		var signalCounts map[speciesRegion]int64
+		if includeSignals {
+			signalCounts, err = recentSignalCounts(db, results, signalDays)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
 		// Transform results
 		marketPrices := make([]MarketPrice, len(results))
 		for i, r := range results {
+			price := r.Price
+			currency := r.Currency
+			// Baselines can come from seafoods quoted in another unit, so
+			// express them per the row's unit before comparing
+			weekAgoPrice := baselineInUnit(r.WeekAgoPrice, r.WeekAgoUnit, r.WeekAgoPieceKg, r.PriceUnit, r.PieceWeightKg)
+			yearAgoPrice := baselineInUnit(r.YearAgoPrice, r.YearAgoUnit, r.YearAgoPieceKg, r.PriceUnit, r.PieceWeightKg)
+			priceUnit := r.PriceUnit
+			var rateDate *string
+			var unitConverted *bool
+			var unitError *string
+			var currencyConverted *bool
+			var currencyError *string
+
+			if rates != nil {
+				converted, convertedOn, ok := rates.Convert(r.Price, r.Currency, targetCurrency, r.Date)
+				if ok {
+					price = converted
+					currency = targetCurrency
+					if !convertedOn.IsZero() {
+						d := convertedOn.Format(dateLayout)
+						rateDate = &d
+					}
+				} else {
+					reason := fmt.Sprintf("no exchange rate from %s to %s on or before %s", r.Currency, targetCurrency, r.Date.Format(dateLayout))
+					currencyError = &reason
+				}
+				currencyConverted = &ok
+				// Baselines follow the row, so trends compare like with like
+				weekAgoPrice = convertBaseline(rates, weekAgoPrice, r.WeekAgoCurrency, r.WeekAgoDate, currency)
+				yearAgoPrice = convertBaseline(rates, yearAgoPrice, r.YearAgoCurrency, r.YearAgoDate, currency)
+			}
+
+			if targetUnit != "" {
+				converted := false
+				if sourceUnit, ok := utils.ParseUnit(r.PriceUnit); !ok {
+					reason := fmt.Sprintf("unknown price unit %q", r.PriceUnit)
+					unitError = &reason
+				} else if factor, err := utils.ConvertPrice(1, sourceUnit, targetUnit, r.PieceWeightKg); err != nil {
+					reason := err.Error()
+					unitError = &reason
+				} else {
+					// Baselines are already per the row's unit, so the row's factor converts them too
+					price *= factor
+					weekAgoPrice = scalePrice(weekAgoPrice, factor)
+					yearAgoPrice = scalePrice(yearAgoPrice, factor)
+					priceUnit = string(targetUnit)
+					converted = true
+				}
+				unitConverted = &converted
+			}
+
 			marketPrices[i] = MarketPrice{
-				SpeciesSKU:  r.SpeciesName,
-				Origin:      r.RegionName,
-				Price:       r.Price,
-				PriceUnit:   r.PriceUnit,
-				WeeklyTrend: utils.CalculateChange(r.Price, r.WeekAgoPrice),
-				YoY:         utils.CalculateChange(r.Price, r.YearAgoPrice),
+				SpeciesSKU:        r.SpeciesName,
+				Origin:            r.RegionName,
+				Price:             price,
+				PriceUnit:         priceUnit,
+				UnitConverted:     unitConverted,
+				UnitError:         unitError,
+				Currency:          currency,
+				RateDate:          rateDate,
+				CurrencyConverted: currencyConverted,
+				CurrencyError:     currencyError,
+				WeeklyTrend:       utils.CalculateChange(price, weekAgoPrice),
+				YoY:               utils.CalculateChange(price, yearAgoPrice),
+			}
+			if groupByCategory {
+				marketPrices[i].Category = r.CategoryName
+			}
+			if groupBySubRegion {
+				marketPrices[i].SubRegion = r.SubRegionName
 			}
+			if signalCounts != nil {
+				count := signalCounts[speciesRegion{SpeciesID: r.SpeciesID, RegionID: r.RegionID}] +
+					signalCounts[speciesRegion{SpeciesID: r.SpeciesID}]
+				marketPrices[i].RecentSignals = &count
+			}
+		}
+
+		if format != "" {
+			exportMarketPrices(c, format, marketPrices, groupByCategory, groupBySubRegion)
+			return
 		}
 
 		// Calculate total pages
@@ -214,3 +526,147 @@ func GetMarketPricesOptimized(db *gorm.DB) gin.HandlerFunc {
 		})
 	}
 }
+
+// scalePrice multiplies an optional price by a conversion factor
+// baselineInUnit expresses a baseline price per toUnit, converting from the unit
+// it was quoted in. Each side uses its own piece weight for per-unit prices.
+// It returns nil when the two units cannot be reconciled.
+func baselineInUnit(price *float64, fromUnit *string, fromPieceKg *float64, toUnit string, toPieceKg *float64) *float64 {
+	if price == nil || fromUnit == nil {
+		return nil
+	}
+	if strings.EqualFold(strings.TrimSpace(*fromUnit), strings.TrimSpace(toUnit)) {
+		return price
+	}
+	from, ok := utils.ParseUnit(*fromUnit)
+	if !ok {
+		return nil
+	}
+	to, ok := utils.ParseUnit(toUnit)
+	if !ok {
+		return nil
+	}
+	perKg, err := utils.ConvertPrice(*price, from, utils.UnitKilogram, fromPieceKg)
+	if err != nil {
+		return nil
+	}
+	converted, err := utils.ConvertPrice(perKg, utils.UnitKilogram, to, toPieceKg)
+	if err != nil {
+		return nil
+	}
+	return &converted
+}
+
+func scalePrice(price *float64, factor float64) *float64 {
+	if price == nil {
+		return nil
+	}
+	scaled := *price * factor
+	return &scaled
+}
+
+// exportMarketPrices writes the transformed market prices as a CSV or XLSX download,
+// with category and sub_region columns when prices were grouped by them
+func exportMarketPrices(c *gin.Context, format string, marketPrices []MarketPrice, withCategory, withSubRegion bool) {
+	header := []string{"species_sku", "origin"}
+	if withCategory {
+		header = append(header, "category")
+	}
+	if withSubRegion {
+		header = append(header, "sub_region")
+	}
+	header = append(header, "price", "price_unit", "unit_converted", "unit_conversion_error", "currency", "rate_date", "currency_converted", "currency_error", "weekly_trend", "yoy", "recent_signals")
+
+	w, err := newTableWriter(c, format, "market-prices", header)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for _, mp := range marketPrices {
+		row := []interface{}{mp.SpeciesSKU, mp.Origin}
+		if withCategory {
+			row = append(row, mp.Category)
+		}
+		if withSubRegion {
+			row = append(row, mp.SubRegion)
+		}
+		row = append(row, mp.Price, mp.PriceUnit, mp.UnitConverted, mp.UnitError, mp.Currency, mp.RateDate, mp.CurrencyConverted, mp.CurrencyError, mp.WeeklyTrend, mp.YoY, mp.RecentSignals)
+		if err := w.WriteRow(row...); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+	if err := w.Close(); err != nil {
+		c.Error(err)
+	}
+}
+
+// speciesRegion keys signal counts by the species and region of a price row
+type speciesRegion struct {
+	SpeciesID uint
+	RegionID  uint
+}
+
+type speciesRegionSignalCount struct {
+	SpeciesID uint
+	RegionID  *uint
+	Count     int64
+}
+
+// recentSignalCounts counts signals published within the last `days` days that
+// are linked to each row's species and region. Signals linked to a species but
+// to no region are not about any one market, so they count for every region.
+func recentSignalCounts(db *gorm.DB, results []MarketPriceResult, days int) (map[speciesRegion]int64, error) {
+	counts := make(map[speciesRegion]int64)
+	if len(results) == 0 {
+		return counts, nil
+	}
+
+	// Rows repeat species and regions, so only distinct ids are bound
+	var speciesIDs, regionIDs []uint
+	seenSpecies := make(map[uint]bool)
+	seenRegions := make(map[uint]bool)
+	for _, r := range results {
+		if !seenSpecies[r.SpeciesID] {
+			seenSpecies[r.SpeciesID] = true
+			speciesIDs = append(speciesIDs, r.SpeciesID)
+		}
+		if !seenRegions[r.RegionID] {
+			seenRegions[r.RegionID] = true
+			regionIDs = append(regionIDs, r.RegionID)
+		}
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	for _, speciesBatch := range idBatches(speciesIDs) {
+		for _, regionBatch := range idBatches(regionIDs) {
+			var rows []speciesRegionSignalCount
+			err := db.Raw(`
+				SELECT mss.species_id, msr.region_id, COUNT(DISTINCT ms.id) AS count
+				FROM market_signal_species mss
+				JOIN market_signals ms ON ms.id = mss.market_signal_id
+				LEFT JOIN market_signal_regions msr ON msr.market_signal_id = ms.id
+				WHERE ms.deleted_at IS NULL
+				  AND ms.published_date >= ?
+				  AND mss.species_id IN ?
+				  AND (msr.region_id IS NULL OR msr.region_id IN ?)
+				GROUP BY mss.species_id, msr.region_id`, since, speciesBatch, regionBatch).Scan(&rows).Error
+			if err != nil {
+				return nil, err
+			}
+
+			// Region-less signals are kept under region 0; callers add them to every region
+			for _, r := range rows {
+				key := speciesRegion{SpeciesID: r.SpeciesID}
+				if r.RegionID != nil {
+					key.RegionID = *r.RegionID
+				} else if regionBatch[0] != regionIDs[0] {
+					// Counted already with the first region batch
+					continue
+				}
+				counts[key] += r.Count
+			}
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBaselineInUnit(t *testing.T) {
+	price := func(v float64) *float64 { return &v }
+	unit := func(u string) *string { return &u }
+
+	tests := []struct {
+		name        string
+		price       *float64
+		fromUnit    *string
+		fromPieceKg *float64
+		toUnit      string
+		toPieceKg   *float64
+		want        *float64
+	}{
+		{"same unit", price(10), unit("kg"), nil, "kg", nil, price(10)},
+		{"same unit, different spelling", price(10), unit(" KG "), nil, "kg", nil, price(10)},
+		{"per lb to per kg", price(10), unit("lb"), nil, "kg", nil, price(10 / 0.45359237)},
+		{"per piece to per kg", price(3), unit("unit"), price(0.5), "kg", nil, price(6)},
+		{"per kg to per piece uses the row's weight", price(6), unit("kg"), nil, "unit", price(0.25), price(1.5)},
+		{"per piece without weight", price(3), unit("unit"), nil, "kg", nil, nil},
+		{"unknown baseline unit", price(3), unit("crate"), nil, "kg", nil, nil},
+		{"unknown row unit", price(3), unit("kg"), nil, "crate", nil, nil},
+		{"no baseline", nil, unit("kg"), nil, "kg", nil, nil},
+		{"baseline without unit", price(3), nil, nil, "kg", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := baselineInUnit(tt.price, tt.fromUnit, tt.fromPieceKg, tt.toUnit, tt.toPieceKg)
+			switch {
+			case got == nil && tt.want == nil:
+			case got == nil || tt.want == nil:
+				t.Fatalf("baselineInUnit = %v, want %v", got, tt.want)
+			case math.Abs(*got-*tt.want) > 1e-9:
+				t.Errorf("baselineInUnit = %v, want %v", *got, *tt.want)
+			}
+		})
+	}
+}
@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
 	"gorm.io/gorm"
 )
 
@@ -20,34 +24,357 @@ func (cd CustomDate) MarshalJSON() ([]byte, error) {
 
 // ----------- Response Struct -----------
 type MarketSignalResponse struct {
+	ID            uint       `json:"id"`
 	Title         string     `json:"title"`
 	PublishedDate CustomDate `json:"published_date"`
+	Author        string     `json:"author"`
+	Summary       string     `json:"summary"`
+	SourceName    string     `json:"source_name"`
+	SourceURL     string     `json:"source_url"`
+	Tags          []string   `gorm:"-" json:"tags"`
+}
+
+type SignalLinkedEntity struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type MarketSignalDetailResponse struct {
+	MarketSignalResponse
+	Body         string               `json:"body"`
+	Species      []SignalLinkedEntity `json:"species"`
+	Regions      []SignalLinkedEntity `json:"regions"`
+	LandingNames []SignalLinkedEntity `json:"landing_names"`
+}
+
+type marketSignalTagRow struct {
+	MarketSignalID uint
+	Tag            string
+}
+
+// ----------- Filters -----------
+
+// marketSignalsFilter builds the WHERE clause for the signal list from the tag,
+// author, from, to, species_id, region_id and landing_name_id query parameters
+func marketSignalsFilter(c *gin.Context) (string, []interface{}, int, error) {
+	from, err := parseDateParam(c, "from")
+	if err != nil {
+		return "", nil, 0, err
+	}
+	to, err := parseDateParam(c, "to")
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	var filterConditions []string
+	var filterArgs []interface{}
+	argIndex := 1
+
+	if tag := models.NormalizeTag(c.Query("tag")); tag != "" {
+		filterConditions = append(filterConditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM market_signal_tags mst
+			WHERE mst.market_signal_id = ms.id AND mst.tag = $%d)`, argIndex))
+		filterArgs = append(filterArgs, tag)
+		argIndex++
+	}
+
+	if author := strings.TrimSpace(c.Query("author")); author != "" {
+		filterConditions = append(filterConditions, fmt.Sprintf("ms.author ILIKE $%d", argIndex))
+		filterArgs = append(filterArgs, "%"+author+"%")
+		argIndex++
+	}
+
+	if from != nil {
+		filterConditions = append(filterConditions, fmt.Sprintf("ms.published_date >= $%d", argIndex))
+		filterArgs = append(filterArgs, *from)
+		argIndex++
+	}
+
+	if to != nil {
+		filterConditions = append(filterConditions, fmt.Sprintf("ms.published_date < $%d", argIndex))
+		filterArgs = append(filterArgs, to.AddDate(0, 0, 1))
+		argIndex++
+	}
+
+	// Linked entity filters, each backed by its join table
+	for _, link := range signalLinks {
+		v := strings.TrimSpace(c.Query(link.param))
+		if v == "" {
+			continue
+		}
+		var id uint
+		if _, err := fmt.Sscanf(v, "%d", &id); err != nil {
+			return "", nil, 0, fmt.Errorf("invalid %s", link.param)
+		}
+		filterConditions = append(filterConditions, signalLinkCondition(link, argIndex))
+		filterArgs = append(filterArgs, id)
+		argIndex++
+	}
+
+	whereClause := "ms.deleted_at IS NULL"
+	if len(filterConditions) > 0 {
+		whereClause += " AND " + strings.Join(filterConditions, " AND ")
+	}
+
+	return whereClause, filterArgs, argIndex, nil
+}
+
+type signalLink struct {
+	param     string
+	joinTable string
+	column    string
+}
+
+// Entities a market signal can be linked to
+var signalLinks = []signalLink{
+	{param: "species_id", joinTable: "market_signal_species", column: "species_id"},
+	{param: "region_id", joinTable: "market_signal_regions", column: "region_id"},
+	{param: "landing_name_id", joinTable: "market_signal_landing_names", column: "landing_name_id"},
+}
+
+func signalLinkCondition(link signalLink, argIndex int) string {
+	return fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM %s msl
+			WHERE msl.market_signal_id = ms.id AND msl.%s = $%d)`, link.joinTable, link.column, argIndex)
 }
 
 // ----------- Handler -----------
 func GetMarketSignals(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		whereClause, filterArgs, argIndex, err := marketSignalsFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		respondMarketSignals(c, db, whereClause, filterArgs, argIndex)
+	}
+}
+
+// GetSpeciesMarketSignals lists the signals linked to one market-price species
+func GetSpeciesMarketSignals(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var speciesID uint
+		if _, err := fmt.Sscanf(c.Param("id"), "%d", &speciesID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid species id"})
+			return
+		}
+
+		whereClause, filterArgs, argIndex, err := marketSignalsFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		whereClause += " AND " + signalLinkCondition(signalLinks[0], argIndex)
+		filterArgs = append(filterArgs, speciesID)
+		argIndex++
+
+		respondMarketSignals(c, db, whereClause, filterArgs, argIndex)
+	}
+}
+
+// respondMarketSignals runs the signal list query and writes it as JSON or as an export
+func respondMarketSignals(c *gin.Context, db *gorm.DB, whereClause string, filterArgs []interface{}, argIndex int) {
+	format, err := exportFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stmt := fmt.Sprintf(`
+		SELECT
+			ms.id,
+			ms.title,
+			ms.published_date,
+			COALESCE(ms.author, '') AS author,
+			COALESCE(ms.summary, '') AS summary,
+			COALESCE(ms.source_name, '') AS source_name,
+			COALESCE(ms.source_url, '') AS source_url
+		FROM market_signals ms
+		WHERE %s
+		ORDER BY ms.published_date DESC, ms.title ASC
+		LIMIT $%d`, whereClause, argIndex)
+
+	// Exports ignore the limit and return up to maxExportRows signals
+	limit := listLimit(c, 100)
+	if format != "" {
+		limit = maxExportRows
+	}
+
+	var results []MarketSignalResponse
+	if err := db.Raw(stmt, append(filterArgs, limit)...).Scan(&results).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := attachSignalTags(db, results); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if format != "" {
+		exportMarketSignals(c, format, results)
+		return
+	}
+
+	if results == nil {
+		results = []MarketSignalResponse{}
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+func GetMarketSignal(db *gorm.DB) gin.HandlerFunc {
 	stmt := `
-		SELECT 
+		SELECT
+			id,
 			title,
-			published_date
+			published_date,
+			COALESCE(author, '') AS author,
+			COALESCE(summary, '') AS summary,
+			COALESCE(source_name, '') AS source_name,
+			COALESCE(source_url, '') AS source_url,
+			COALESCE(body, '') AS body
 		FROM market_signals
-		WHERE deleted_at IS NULL
-		ORDER BY published_date DESC, title ASC
-		LIMIT $1
+		WHERE id = $1
+		  AND deleted_at IS NULL
 	`
 
 	return func(c *gin.Context) {
-		limit := 100
-		if l := c.Query("limit"); l != "" {
-			fmt.Sscanf(l, "%d", &limit)
+		var id uint
+		if _, err := fmt.Sscanf(c.Param("id"), "%d", &id); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid signal id"})
+			return
+		}
+
+		var result MarketSignalDetailResponse
+		if err := db.Raw(stmt, id).Row().Scan(
+			&result.ID,
+			&result.Title,
+			(*time.Time)(&result.PublishedDate),
+			&result.Author,
+			&result.Summary,
+			&result.SourceName,
+			&result.SourceURL,
+			&result.Body,
+		); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Market signal not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
 		}
 
-		var results []MarketSignalResponse
-		if err := db.Raw(stmt, limit).Scan(&results).Error; err != nil {
+		signals := []MarketSignalResponse{result.MarketSignalResponse}
+		if err := attachSignalTags(db, signals); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
+		result.MarketSignalResponse = signals[0]
+
+		// Linked entities
+		links := []struct {
+			dest *[]SignalLinkedEntity
+			stmt string
+		}{
+			{&result.Species, `
+				SELECT sp.id, sp.name
+				FROM market_signal_species mss
+				JOIN species sp ON sp.id = mss.species_id
+				WHERE mss.market_signal_id = $1 AND sp.deleted_at IS NULL
+				ORDER BY sp.name ASC`},
+			{&result.Regions, `
+				SELECT r.id, r.region AS name
+				FROM market_signal_regions msr
+				JOIN regions r ON r.id = msr.region_id
+				WHERE msr.market_signal_id = $1 AND r.deleted_at IS NULL
+				ORDER BY r.region ASC`},
+			{&result.LandingNames, `
+				SELECT ln.id, ln.nmfs_name AS name
+				FROM market_signal_landing_names msln
+				JOIN landing_names ln ON ln.id = msln.landing_name_id
+				WHERE msln.market_signal_id = $1 AND ln.deleted_at IS NULL
+				ORDER BY ln.nmfs_name ASC`},
+		}
+		for _, link := range links {
+			*link.dest = []SignalLinkedEntity{}
+			if err := db.Raw(link.stmt, id).Scan(link.dest).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// attachSignalTags loads the tags for a page of signals, one query per idBatchSize signals
+func attachSignalTags(db *gorm.DB, signals []MarketSignalResponse) error {
+	if len(signals) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(signals))
+	byID := make(map[uint]int, len(signals))
+	for i, s := range signals {
+		ids[i] = s.ID
+		byID[s.ID] = i
+		signals[i].Tags = []string{}
+	}
+
+	for _, batch := range idBatches(ids) {
+		var rows []marketSignalTagRow
+		if err := db.Raw(`
+			SELECT market_signal_id, tag
+			FROM market_signal_tags
+			WHERE market_signal_id IN ?
+			ORDER BY tag ASC`, batch).Scan(&rows).Error; err != nil {
+			return err
+		}
+
+		for _, r := range rows {
+			if i, ok := byID[r.MarketSignalID]; ok {
+				signals[i].Tags = append(signals[i].Tags, r.Tag)
+			}
+		}
+	}
+	return nil
+}
+
+// idBatchSize keeps IN lists well under Postgres' 65,535 bind parameter limit
+const idBatchSize = 1000
+
+// idBatches splits ids into slices of at most idBatchSize
+func idBatches(ids []uint) [][]uint {
+	var batches [][]uint
+	for len(ids) > idBatchSize {
+		batches = append(batches, ids[:idBatchSize])
+		ids = ids[idBatchSize:]
+	}
+	if len(ids) > 0 {
+		batches = append(batches, ids)
+	}
+	return batches
+}
 
-		c.JSON(http.StatusOK, results)
+// exportMarketSignals writes market signals as a CSV or XLSX download
+func exportMarketSignals(c *gin.Context, format string, results []MarketSignalResponse) {
+	w, err := newTableWriter(c, format, "market-signals", []string{
+		"id", "title", "published_date", "author", "summary", "source_name", "source_url", "tags",
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for _, r := range results {
+		if err := w.WriteRow(r.ID, r.Title, r.PublishedDate, r.Author, r.Summary, r.SourceName, r.SourceURL, strings.Join(r.Tags, ", ")); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+	if err := w.Close(); err != nil {
+		c.Error(err)
 	}
 }
@@ -0,0 +1,45 @@
+package handlers
+
+import "testing"
+
+func TestIDBatches(t *testing.T) {
+	ids := func(n int) []uint {
+		out := make([]uint, n)
+		for i := range out {
+			out[i] = uint(i + 1)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name  string
+		ids   []uint
+		sizes []int
+	}{
+		{"empty", nil, nil},
+		{"one batch", ids(3), []int{3}},
+		{"exactly one batch", ids(idBatchSize), []int{idBatchSize}},
+		{"spills over", ids(2*idBatchSize + 1), []int{idBatchSize, idBatchSize, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batches := idBatches(tt.ids)
+			if len(batches) != len(tt.sizes) {
+				t.Fatalf("got %d batches, want %d", len(batches), len(tt.sizes))
+			}
+			next := uint(1)
+			for i, b := range batches {
+				if len(b) != tt.sizes[i] {
+					t.Errorf("batch %d has %d ids, want %d", i, len(b), tt.sizes[i])
+				}
+				for _, id := range b {
+					if id != next {
+						t.Fatalf("batch %d: got id %d, want %d", i, id, next)
+					}
+					next++
+				}
+			}
+		})
+	}
+}
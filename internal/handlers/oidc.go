@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/config"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/database"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/oidc"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/utils"
+)
+
+// oidcStateTTL is how long the user has to finish signing in at the provider
+const oidcStateTTL = 10 * time.Minute
+
+// OIDCLogin starts a single sign-on login. The client sends the user to the
+// returned authorization URL; the provider redirects back to the configured
+// redirect URL with a code and state, which the client posts to OIDCCallback.
+func OIDCLogin(c *gin.Context) {
+	provider, err := oidc.GetProvider(c.Param("provider"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown identity provider"})
+		return
+	}
+
+	// Every login stores a state row, so cap how fast one client can start them
+	window := config.LoginAttemptWindow()
+	started, err := database.CountRecentOIDCLoginStatesByIP(c.ClientIP(), window)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if started >= config.MaxOIDCLoginsPerIP() {
+		c.Header("Retry-After", strconv.Itoa(int(window.Seconds())))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many login attempts, please try again later"})
+		return
+	}
+
+	// State, nonce and PKCE verifier
+	var secrets [3]string
+	for i := range secrets {
+		if secrets[i], err = utils.GenerateRandomToken(32); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start login"})
+			return
+		}
+	}
+	state, nonce, verifier := secrets[0], secrets[1], secrets[2]
+
+	authURL, err := provider.AuthCodeURL(c.Request.Context(), state, nonce, verifier)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Identity provider is unavailable"})
+		return
+	}
+
+	loginState := &models.OIDCLoginState{
+		StateHash:    utils.HashToken(state),
+		Provider:     provider.Name,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		IPAddress:    truncateString(c.ClientIP(), 45),
+		ExpiresAt:    time.Now().Add(oidcStateTTL),
+	}
+	if err := database.CreateOIDCLoginState(loginState); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start login"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.OIDCLoginResponse{
+		AuthorizationURL: authURL,
+		State:            state,
+		ExpiresAt:        loginState.ExpiresAt,
+	})
+}
+
+// OIDCCallback finishes a single sign-on login. The provider account is matched
+// to a user by its subject, then by verified email; a new user is created when
+// neither matches. The response is the same as Login: locked accounts get a 423
+// and accounts with TOTP get a challenge token for POST /login/2fa.
+func OIDCCallback(c *gin.Context) {
+	var req models.OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	provider, err := oidc.GetProvider(c.Param("provider"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown identity provider"})
+		return
+	}
+
+	loginState, err := database.ConsumeOIDCLoginState(utils.HashToken(req.State), provider.Name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			recordAudit(c, auditFailure(models.AuditLoginOIDC, "", "unknown state"))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired login state"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if time.Now().After(loginState.ExpiresAt) {
+		recordAudit(c, auditFailure(models.AuditLoginOIDC, "", "expired state"))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired login state"})
+		return
+	}
+
+	claims, err := provider.Exchange(c.Request.Context(), req.Code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		c.Error(err)
+		recordAudit(c, auditFailure(models.AuditLoginOIDC, "", err.Error()))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Identity provider login failed"})
+		return
+	}
+
+	user, err := oidcUser(provider, claims)
+	if err != nil {
+		if errors.Is(err, errUnverifiedEmail) {
+			recordAudit(c, auditFailure(models.AuditLoginOIDC, claims.Email, err.Error()))
+			c.JSON(http.StatusForbidden, gin.H{"error": "The identity provider has not verified this email address"})
+			return
+		}
+		if errors.Is(err, errUnverifiedAccount) {
+			recordAudit(c, auditFailure(models.AuditLoginOIDC, claims.Email, err.Error()))
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "An account with this email exists but has not verified it; sign in with your password and verify your email before using single sign-on",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sign in user"})
+		return
+	}
+
+	if accountLocked(c, user) {
+		recordAudit(c, auditUserFailure(models.AuditLoginOIDC, user, "account locked"))
+		return
+	}
+
+	// TOTP still applies unless the provider is trusted to have done MFA itself
+	if user.TOTPEnabledAt != nil && !provider.TrustMFA {
+		challenge, expiresAt, err := utils.GenerateChallengeToken(user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+			return
+		}
+		entry := auditUser(models.AuditLoginOIDC, user)
+		entry.Details = "provider=" + provider.Name + ", two-factor challenge issued"
+		recordAudit(c, entry)
+		c.JSON(http.StatusOK, models.TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge,
+			ExpiresAt:         expiresAt,
+		})
+		return
+	}
+
+	recordSuccessfulLogin(c, user)
+
+	// Start a session and generate tokens
+	response, err := issueSession(c, user, req.DeviceName)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	entry := auditUser(models.AuditLoginOIDC, user)
+	entry.Details = "provider=" + provider.Name
+	recordAudit(c, entry)
+
+	c.JSON(http.StatusOK, response)
+}
+
+var (
+	errUnverifiedEmail   = errors.New("email not verified by identity provider")
+	errUnverifiedAccount = errors.New("existing account has not verified its email")
+)
+
+// oidcUser finds, links or creates the user for a verified ID token
+func oidcUser(provider *oidc.Provider, claims *oidc.IDTokenClaims) (*models.User, error) {
+	// Already linked
+	user, err := database.GetUserByIdentity(provider.Issuer, claims.Subject)
+	if err == nil {
+		return user, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	// Only a verified email may be linked to, or create, an account. Providers
+	// don't agree on case, so addresses are compared and stored lower-cased.
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+	if email == "" || !bool(claims.EmailVerified) {
+		return nil, errUnverifiedEmail
+	}
+
+	// An existing account is only linked once it has proven it owns the address,
+	// otherwise whoever registered the email first would inherit the SSO login
+	user, err = database.GetUserByEmailFold(email)
+	if err == nil && user.EmailVerifiedAt == nil {
+		return nil, errUnverifiedAccount
+	}
+	if err == sql.ErrNoRows {
+		user, err = createOIDCUser(email, claims.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := database.LinkUserIdentity(user.ID, provider.Issuer, claims.Subject, email); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// createOIDCUser provisions a user with a random password; they can set a
+// real one later through the password reset flow
+func createOIDCUser(email, name string) (*models.User, error) {
+	if strings.TrimSpace(name) == "" {
+		name = strings.SplitN(email, "@", 2)[0]
+	}
+
+	password, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{Email: email, Name: name}
+	if err := user.HashPassword(password); err != nil {
+		return nil, err
+	}
+	if err := database.CreateUser(user); err != nil {
+		return nil, err
+	}
+
+	// The provider has verified the address
+	if err := database.MarkUserEmailVerified(user.ID); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	return user, nil
+}
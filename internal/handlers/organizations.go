@@ -0,0 +1,383 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/database"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/utils"
+)
+
+const organizationInviteTTL = 7 * 24 * time.Hour
+
+// loadMembership resolves the :id organization for the caller. It writes a 404
+// when the organization does not exist or the caller is not a member, and a
+// 403 when ownerOnly is set and the caller is not an owner.
+func loadMembership(c *gin.Context, ownerOnly bool) (*models.Organization, bool) {
+	var orgID int
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &orgID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization id"})
+		return nil, false
+	}
+
+	org, err := database.GetOrganizationForMember(orgID, c.GetInt("user_id"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Organization not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return nil, false
+	}
+
+	if ownerOnly && org.Role != models.OrgRoleOwner {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only organization owners can do this"})
+		return nil, false
+	}
+	return org, true
+}
+
+// cleanOrganizationName trims a name and writes a 400 when it is empty or has
+// control characters; the name ends up in invite email subjects
+func cleanOrganizationName(c *gin.Context, raw string) (string, bool) {
+	name := strings.TrimSpace(raw)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name cannot be empty"})
+		return "", false
+	}
+	if strings.IndexFunc(name, unicode.IsControl) >= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name cannot contain control characters"})
+		return "", false
+	}
+	return name, true
+}
+
+func CreateOrganization(c *gin.Context) {
+	var req models.OrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	name, ok := cleanOrganizationName(c, req.Name)
+	if !ok {
+		return
+	}
+
+	org := &models.Organization{Name: name}
+	if err := database.CreateOrganization(org, c.GetInt("user_id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create organization"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, org)
+}
+
+func ListOrganizations(c *gin.Context) {
+	orgs, err := database.GetUserOrganizations(c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, orgs)
+}
+
+func GetOrganization(c *gin.Context) {
+	org, ok := loadMembership(c, false)
+	if !ok {
+		return
+	}
+
+	members, err := database.GetOrganizationMembers(org.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.OrganizationDetail{
+		Organization: *org,
+		Members:      members,
+	})
+}
+
+func UpdateOrganization(c *gin.Context) {
+	org, ok := loadMembership(c, true)
+	if !ok {
+		return
+	}
+
+	var req models.OrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	name, ok := cleanOrganizationName(c, req.Name)
+	if !ok {
+		return
+	}
+
+	if err := database.UpdateOrganizationName(org.ID, name); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update organization"})
+		return
+	}
+	org.Name = name
+
+	c.JSON(http.StatusOK, org)
+}
+
+func DeleteOrganization(c *gin.Context) {
+	org, ok := loadMembership(c, true)
+	if !ok {
+		return
+	}
+
+	if err := database.DeleteOrganization(org.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete organization"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Organization deleted"})
+}
+
+// ----------- Members -----------
+
+func UpdateOrganizationMember(c *gin.Context) {
+	org, ok := loadMembership(c, true)
+	if !ok {
+		return
+	}
+
+	var memberID int
+	if _, err := fmt.Sscanf(c.Param("userId"), "%d", &memberID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+
+	var req models.UpdateMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Role != models.OrgRoleOwner && !keepsAnOwner(c, org.ID, memberID) {
+		return
+	}
+
+	if err := database.UpdateMemberRole(org.ID, memberID, req.Role); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member role updated"})
+}
+
+// RemoveOrganizationMember removes a member. Owners can remove anyone; members can only leave.
+func RemoveOrganizationMember(c *gin.Context) {
+	org, ok := loadMembership(c, false)
+	if !ok {
+		return
+	}
+
+	var memberID int
+	if _, err := fmt.Sscanf(c.Param("userId"), "%d", &memberID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+
+	if memberID != c.GetInt("user_id") && org.Role != models.OrgRoleOwner {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only organization owners can do this"})
+		return
+	}
+
+	if !keepsAnOwner(c, org.ID, memberID) {
+		return
+	}
+
+	if err := database.RemoveOrganizationMember(org.ID, memberID); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed"})
+}
+
+// keepsAnOwner writes a 409 and reports false when memberID is the organization's only owner
+func keepsAnOwner(c *gin.Context, orgID, memberID int) bool {
+	role, err := database.GetMemberRole(orgID, memberID)
+	if err == sql.ErrNoRows || (err == nil && role != models.OrgRoleOwner) {
+		return true
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return false
+	}
+
+	owners, err := database.CountOrganizationOwners(orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return false
+	}
+	if owners <= 1 {
+		c.JSON(http.StatusConflict, gin.H{"error": "An organization must keep at least one owner"})
+		return false
+	}
+	return true
+}
+
+// ----------- Invites -----------
+
+func CreateOrganizationInvite(c *gin.Context) {
+	org, ok := loadMembership(c, true)
+	if !ok {
+		return
+	}
+
+	var req models.CreateInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Role == "" {
+		req.Role = models.OrgRoleMember
+	}
+
+	inviter, err := database.GetUserByID(c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate invite token"})
+		return
+	}
+
+	invite := &models.OrganizationInvite{
+		OrganizationID: org.ID,
+		Email:          strings.ToLower(strings.TrimSpace(req.Email)),
+		Role:           req.Role,
+		TokenHash:      utils.HashToken(token),
+		InvitedBy:      &inviter.ID,
+		ExpiresAt:      time.Now().Add(organizationInviteTTL),
+	}
+	if err := database.CreateOrganizationInvite(invite); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invite"})
+		return
+	}
+
+	// Send email
+	if err := utils.SendOrganizationInviteEmail(invite.Email, org.Name, inviter.Name, token); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send invite email"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, invite)
+}
+
+func ListOrganizationInvites(c *gin.Context) {
+	org, ok := loadMembership(c, true)
+	if !ok {
+		return
+	}
+
+	invites, err := database.GetPendingInvites(org.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, invites)
+}
+
+func RevokeOrganizationInvite(c *gin.Context) {
+	org, ok := loadMembership(c, true)
+	if !ok {
+		return
+	}
+
+	var inviteID int
+	if _, err := fmt.Sscanf(c.Param("inviteId"), "%d", &inviteID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invite id"})
+		return
+	}
+
+	if err := database.DeleteOrganizationInvite(org.ID, inviteID); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Invite not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke invite"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invite revoked"})
+}
+
+// AcceptOrganizationInvite joins the caller to the organization. The invite
+// must have been sent to the caller's email address.
+func AcceptOrganizationInvite(c *gin.Context) {
+	var req models.AcceptInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invite, err := database.GetOrganizationInviteByHash(utils.HashToken(req.Token))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired invite"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	if invite.AcceptedAt != nil || time.Now().After(invite.ExpiresAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired invite"})
+		return
+	}
+
+	user, err := database.GetUserByID(c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if !strings.EqualFold(user.Email, invite.Email) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This invite was sent to a different email address"})
+		return
+	}
+
+	if err := database.AcceptOrganizationInvite(invite, user.ID); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired invite"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to accept invite"})
+		return
+	}
+
+	org, err := database.GetOrganizationForMember(invite.OrganizationID, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ----------- Response Struct -----------
+
+type PriceHistoryPoint struct {
+	Date      string  `json:"date"`
+	PriceUnit string  `json:"price_unit"`
+	Currency  string  `json:"currency"`
+	Avg       float64 `json:"avg"`
+	Min       float64 `json:"min"`
+	Max       float64 `json:"max"`
+	Close     float64 `json:"close"`
+	Count     int     `json:"count"`
+}
+
+type PriceHistoryResponse struct {
+	SpeciesSKU string              `json:"species_sku"`
+	Origin     string              `json:"origin"`
+	Interval   string              `json:"interval"`
+	From       *string             `json:"from"`
+	To         *string             `json:"to"`
+	Data       []PriceHistoryPoint `json:"data"`
+}
+
+type priceHistoryRow struct {
+	Bucket      time.Time
+	PriceUnit   string
+	Currency    string
+	SpeciesName string
+	RegionName  string
+	AvgPrice    float64
+	MinPrice    float64
+	MaxPrice    float64
+	ClosePrice  float64
+	Count       int
+}
+
+// Bucket sizes accepted by the interval parameter, mapped to date_trunc fields
+var priceHistoryIntervals = map[string]string{
+	"daily":   "day",
+	"weekly":  "week",
+	"monthly": "month",
+}
+
+const dateLayout = "2006-01-02"
+
+// parseDateParam parses an optional YYYY-MM-DD query parameter
+func parseDateParam(c *gin.Context, name string) (*time.Time, error) {
+	v := strings.TrimSpace(c.Query(name))
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(dateLayout, v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s date, expected YYYY-MM-DD", name)
+	}
+	return &t, nil
+}
+
+// ----------- Handler -----------
+
+func GetMarketPriceHistory(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		interval := strings.ToLower(strings.TrimSpace(c.DefaultQuery("interval", "daily")))
+		truncField, ok := priceHistoryIntervals[interval]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "interval must be one of daily, weekly, monthly"})
+			return
+		}
+
+		from, err := parseDateParam(c, "from")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		to, err := parseDateParam(c, "to")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if from != nil && to != nil && to.Before(*from) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must not be before from"})
+			return
+		}
+
+		// Build WHERE clause for filters
+		var filterConditions []string
+		var filterArgs []interface{}
+		argIndex := 1
+
+		baseWhereClause := `p.deleted_at IS NULL
+			  AND s.deleted_at IS NULL
+			  AND sp.deleted_at IS NULL
+			  AND r.deleted_at IS NULL`
+
+		// Species and region may be given by id or by exact (case-insensitive) name
+		speciesID := strings.TrimSpace(c.Query("species_id"))
+		speciesName := strings.TrimSpace(c.Query("species"))
+		regionID := strings.TrimSpace(c.Query("region_id"))
+		regionName := strings.TrimSpace(c.Query("region"))
+
+		switch {
+		case speciesID != "":
+			var id uint
+			if _, err := fmt.Sscanf(speciesID, "%d", &id); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid species_id"})
+				return
+			}
+			filterConditions = append(filterConditions, fmt.Sprintf("s.species_id = $%d", argIndex))
+			filterArgs = append(filterArgs, id)
+			argIndex++
+		case speciesName != "":
+			filterConditions = append(filterConditions, fmt.Sprintf(`sp.name ILIKE $%d ESCAPE '\'`, argIndex))
+			filterArgs = append(filterArgs, escapeLike(speciesName))
+			argIndex++
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "species or species_id is required"})
+			return
+		}
+
+		switch {
+		case regionID != "":
+			var id uint
+			if _, err := fmt.Sscanf(regionID, "%d", &id); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid region_id"})
+				return
+			}
+			filterConditions = append(filterConditions, fmt.Sprintf("s.region_id = $%d", argIndex))
+			filterArgs = append(filterArgs, id)
+			argIndex++
+		case regionName != "":
+			filterConditions = append(filterConditions, fmt.Sprintf(`r.region ILIKE $%d ESCAPE '\'`, argIndex))
+			filterArgs = append(filterArgs, escapeLike(regionName))
+			argIndex++
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "region or region_id is required"})
+			return
+		}
+
+		if priceUnit := strings.TrimSpace(c.Query("price_unit")); priceUnit != "" {
+			filterConditions = append(filterConditions, fmt.Sprintf("s.price_unit = $%d", argIndex))
+			filterArgs = append(filterArgs, priceUnit)
+			argIndex++
+		}
+
+		if from != nil {
+			filterConditions = append(filterConditions, fmt.Sprintf("p.date >= $%d", argIndex))
+			filterArgs = append(filterArgs, *from)
+			argIndex++
+		}
+
+		if to != nil {
+			// Inclusive upper bound on the calendar day
+			filterConditions = append(filterConditions, fmt.Sprintf("p.date < $%d", argIndex))
+			filterArgs = append(filterArgs, to.AddDate(0, 0, 1))
+			argIndex++
+		}
+
+		whereClause := baseWhereClause + " AND " + strings.Join(filterConditions, " AND ")
+
+		// Prices with different units or currencies are never mixed within a bucket
+		stmt := fmt.Sprintf(`
+			SELECT
+				date_trunc('%s', p.date) AS bucket,
+				s.price_unit,
+				p.currency,
+				MIN(sp.name) AS species_name,
+				MIN(r.region) AS region_name,
+				AVG(p.price) AS avg_price,
+				MIN(p.price) AS min_price,
+				MAX(p.price) AS max_price,
+				(ARRAY_AGG(p.price ORDER BY p.date DESC, p.id DESC))[1] AS close_price,
+				COUNT(*) AS count
+			FROM prices p
+			JOIN seafoods s ON p.seafood_id = s.id
+			JOIN species sp ON s.species_id = sp.id
+			JOIN regions r ON s.region_id = r.id
+			WHERE %s
+			GROUP BY bucket, s.price_unit, p.currency
+			ORDER BY bucket ASC, s.price_unit ASC, p.currency ASC`, truncField, whereClause)
+
+		var rows []priceHistoryRow
+		if err := db.Raw(stmt, filterArgs...).Scan(&rows).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := PriceHistoryResponse{
+			Interval: interval,
+			Data:     make([]PriceHistoryPoint, len(rows)),
+		}
+		if from != nil {
+			f := from.Format(dateLayout)
+			response.From = &f
+		}
+		if to != nil {
+			t := to.Format(dateLayout)
+			response.To = &t
+		}
+
+		for i, r := range rows {
+			if i == 0 {
+				response.SpeciesSKU = r.SpeciesName
+				response.Origin = r.RegionName
+			}
+			response.Data[i] = PriceHistoryPoint{
+				Date:      r.Bucket.Format(dateLayout),
+				PriceUnit: r.PriceUnit,
+				Currency:  r.Currency,
+				Avg:       r.AvgPrice,
+				Min:       r.MinPrice,
+				Max:       r.MaxPrice,
+				Close:     r.ClosePrice,
+				Count:     r.Count,
+			}
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/database"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+)
+
+// UpdateProfile changes the caller's name and/or email. A new email needs the
+// current password and is kept as pending_email until the link sent to it is
+// followed; the account keeps its current, verified email until then.
+func UpdateProfile(c *gin.Context) {
+	var req models.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt("user_id")
+
+	user, err := database.GetUserByID(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Name cannot be empty"})
+			return
+		}
+		if err := database.UpdateUserName(userID, name); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
+			return
+		}
+		user.Name = name
+	}
+
+	if req.Email != nil && !strings.EqualFold(*req.Email, user.Email) {
+		if !user.CheckPassword(req.CurrentPassword) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Current password is required to change email"})
+			return
+		}
+
+		// Check if email is taken
+		if _, err := database.GetUserByEmail(*req.Email); err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Email already exists"})
+			return
+		} else if err != sql.ErrNoRows {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+
+		email := *req.Email
+		if err := database.SetPendingEmail(userID, email); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
+			return
+		}
+		user.PendingEmail = &email
+
+		if err := sendEmailChangeVerification(user, email); err != nil {
+			c.Error(err)
+		}
+	}
+
+	recordAudit(c, auditUser(models.AuditProfileUpdate, user))
+
+	c.JSON(http.StatusOK, user)
+}
+
+// ChangePassword sets a new password after checking the current one. Every
+// session is signed out and the caller gets a fresh one.
+func ChangePassword(c *gin.Context) {
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt("user_id")
+
+	user, err := database.GetUserByID(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if !user.CheckPassword(req.CurrentPassword) {
+		recordAudit(c, auditUserFailure(models.AuditPasswordChange, user, "invalid current password"))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Current password is incorrect"})
+		return
+	}
+
+	if err := user.HashPassword(req.NewPassword); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process password"})
+		return
+	}
+
+	if err := database.UpdateUserPassword(userID, user.Password); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update password"})
+		return
+	}
+
+	// Sign out every device that used the old password
+	if err := database.RevokeAllSessions(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+		return
+	}
+
+	// Start a session and generate tokens
+	response, err := issueSession(c, user, req.DeviceName)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	recordAudit(c, auditUser(models.AuditPasswordChange, user))
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteProfile permanently deletes the caller's account
+func DeleteProfile(c *gin.Context) {
+	var req models.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt("user_id")
+
+	user, err := database.GetUserByID(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if !user.CheckPassword(req.Password) {
+		recordAudit(c, auditUserFailure(models.AuditAccountDelete, user, "invalid password"))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+		return
+	}
+
+	if user.TOTPEnabledAt != nil {
+		ok, err := verifySecondFactor(userID, req.Code)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+		if !ok {
+			recordAudit(c, auditUserFailure(models.AuditAccountDelete, user, "invalid code"))
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid code"})
+			return
+		}
+	}
+
+	// Keep at least one admin around
+	if user.Role == models.RoleAdmin {
+		admins, err := database.CountUsersWithRole(models.RoleAdmin)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+		if admins <= 1 {
+			c.JSON(http.StatusConflict, gin.H{"error": "The last admin account cannot be deleted"})
+			return
+		}
+	}
+
+	// Don't leave an organization without an owner
+	orgs, err := database.GetUserOrganizations(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	for _, org := range orgs {
+		if org.Role != models.OrgRoleOwner {
+			continue
+		}
+		owners, err := database.CountOrganizationOwners(org.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			return
+		}
+		if owners <= 1 {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": fmt.Sprintf("You are the only owner of %s; transfer ownership or delete the organization first", org.Name),
+			})
+			return
+		}
+	}
+
+	if err := database.DeleteUser(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete account"})
+		return
+	}
+
+	recordAudit(c, auditUser(models.AuditAccountDelete, user))
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account deleted successfully"})
+}
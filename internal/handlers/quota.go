@@ -3,8 +3,11 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/utils"
 	"gorm.io/gorm"
 )
 
@@ -30,9 +33,16 @@ func GetQuotas(db *gorm.DB) gin.HandlerFunc {
 	`
 
 	return func(c *gin.Context) {
-		limit := 100
-		if l := c.Query("limit"); l != "" {
-			fmt.Sscanf(l, "%d", &limit)
+		format, err := exportFormat(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Exports ignore the limit and return every quota
+		var limit interface{} = listLimit(c, 100)
+		if format != "" {
+			limit = nil
 		}
 
 		var results []QuotaResponse
@@ -41,6 +51,157 @@ func GetQuotas(db *gorm.DB) gin.HandlerFunc {
 			return
 		}
 
+		if format != "" {
+			exportQuotas(c, format, results)
+			return
+		}
+
 		c.JSON(http.StatusOK, results)
 	}
 }
+
+// exportQuotas writes quotas as a CSV or XLSX download
+func exportQuotas(c *gin.Context, format string, results []QuotaResponse) {
+	w, err := newTableWriter(c, format, "quotas", []string{"date", "product_name", "remaining_quota"})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for _, r := range results {
+		if err := w.WriteRow(r.Date, r.ProductName, r.RemainingQuota); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+	if err := w.Close(); err != nil {
+		c.Error(err)
+	}
+}
+
+// ----------- History -----------
+
+type QuotaHistoryPoint struct {
+	Date           string  `json:"date"`
+	RemainingQuota float64 `json:"remaining_quota"`
+}
+
+type QuotaHistoryResponse struct {
+	ProductName             string              `json:"product_name"`
+	Data                    []QuotaHistoryPoint `json:"data"`
+	BurnRateWindowDays      int                 `json:"burn_rate_window_days"`
+	DailyBurnRate           *float64            `json:"daily_burn_rate"`
+	DaysToExhaustion        *float64            `json:"days_to_exhaustion"`
+	ProjectedExhaustionDate *string             `json:"projected_exhaustion_date"`
+}
+
+type quotaHistoryRow struct {
+	Date           time.Time
+	ProductName    string
+	RemainingQuota float64
+}
+
+func GetQuotaHistory(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		product := strings.TrimSpace(c.Param("product"))
+		if product == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "product is required"})
+			return
+		}
+
+		from, err := parseDateParam(c, "from")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		to, err := parseDateParam(c, "to")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		window := 30
+		if w := c.Query("window_days"); w != "" {
+			fmt.Sscanf(w, "%d", &window)
+			if window < 2 {
+				window = 30
+			}
+		}
+
+		filterConditions := []string{"deleted_at IS NULL", "LOWER(product_name) = LOWER($1)"}
+		filterArgs := []interface{}{product}
+		argIndex := 2
+
+		if from != nil {
+			filterConditions = append(filterConditions, fmt.Sprintf("date >= $%d", argIndex))
+			filterArgs = append(filterArgs, *from)
+			argIndex++
+		}
+
+		if to != nil {
+			filterConditions = append(filterConditions, fmt.Sprintf("date < $%d", argIndex))
+			filterArgs = append(filterArgs, to.AddDate(0, 0, 1))
+			argIndex++
+		}
+
+		// The last reading of each day; quota can go up when it resets
+		stmt := fmt.Sprintf(`
+			SELECT DISTINCT ON (date::date)
+				date::date AS date,
+				product_name,
+				remaining_quota
+			FROM quota
+			WHERE %s
+			ORDER BY date::date ASC, date DESC, id DESC`, strings.Join(filterConditions, " AND "))
+
+		var rows []quotaHistoryRow
+		if err := db.Raw(stmt, filterArgs...).Scan(&rows).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if len(rows) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No quota history found for product"})
+			return
+		}
+
+		response := QuotaHistoryResponse{
+			ProductName:        rows[0].ProductName,
+			Data:               make([]QuotaHistoryPoint, len(rows)),
+			BurnRateWindowDays: window,
+		}
+		for i, r := range rows {
+			response.Data[i] = QuotaHistoryPoint{
+				Date:           r.Date.Format(dateLayout),
+				RemainingQuota: r.RemainingQuota,
+			}
+		}
+
+		// Burn rate is the fitted daily decline over the trailing window,
+		// starting after the last reset so a refill does not flatten the slope
+		latest := rows[len(rows)-1]
+		windowStart := latest.Date.AddDate(0, 0, -window)
+		var xs, ys []float64
+		for _, r := range rows {
+			if r.Date.Before(windowStart) {
+				continue
+			}
+			xs = append(xs, r.Date.Sub(windowStart).Hours()/24)
+			ys = append(ys, r.RemainingQuota)
+		}
+		reset := utils.LastResetIndex(ys)
+		xs, ys = xs[reset:], ys[reset:]
+
+		if slope, ok := utils.LinearSlope(xs, ys); ok {
+			burn := -slope
+			response.DailyBurnRate = &burn
+
+			if days, date, ok := utils.ProjectExhaustion(latest.Date, latest.RemainingQuota, burn); ok {
+				exhaustion := date.Format(dateLayout)
+				response.DaysToExhaustion = &days
+				response.ProjectedExhaustionDate = &exhaustion
+			}
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
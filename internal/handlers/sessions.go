@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/database"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/utils"
+)
+
+// issueSession records a new device session for the user and returns its access and refresh tokens
+func issueSession(c *gin.Context, user *models.User, deviceName string) (*models.LoginResponse, error) {
+	refreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.Session{
+		UserID:           user.ID,
+		RefreshTokenHash: utils.HashToken(refreshToken),
+		DeviceName:       truncateString(deviceName, 255),
+		UserAgent:        c.Request.UserAgent(),
+		IPAddress:        c.ClientIP(),
+		ExpiresAt:        time.Now().Add(utils.RefreshTokenTTL()),
+	}
+	if err := database.CreateSession(session); err != nil {
+		return nil, err
+	}
+
+	token, expiresAt, err := utils.GenerateToken(user.ID, user.Email, user.Role, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoginResponse{
+		Token:            token,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
+		User:             *user,
+	}, nil
+}
+
+// auditSession is the audit entry for an action by user on one of their sessions
+func auditSession(action string, user *models.User, sessionID int) models.AuditLogEntry {
+	return models.AuditLogEntry{
+		Action:      action,
+		ActorUserID: &user.ID,
+		ActorEmail:  user.Email,
+		TargetType:  "session",
+		TargetID:    fmt.Sprint(sessionID),
+	}
+}
+
+func truncateString(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max])
+}
+
+func RefreshToken(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get session
+	oldHash := utils.HashToken(req.RefreshToken)
+	session, err := database.GetSessionByRefreshTokenHash(oldHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			recordAudit(c, auditFailure(models.AuditTokenRefresh, "", "unknown refresh token"))
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	if session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		entry := auditFailure(models.AuditTokenRefresh, "", "session expired or revoked")
+		entry.ActorUserID = &session.UserID
+		entry.TargetType = "session"
+		entry.TargetID = fmt.Sprint(session.ID)
+		recordAudit(c, entry)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has expired or been revoked"})
+		return
+	}
+
+	user, err := database.GetUserByID(session.UserID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	// Rotate the refresh token so each one can only be used once
+	newRefreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+	refreshExpiresAt := time.Now().Add(utils.RefreshTokenTTL())
+	if err := database.RotateSessionRefreshToken(session.ID, oldHash, utils.HashToken(newRefreshToken), refreshExpiresAt); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh session"})
+		return
+	}
+
+	token, expiresAt, err := utils.GenerateToken(user.ID, user.Email, user.Role, session.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	recordAudit(c, auditSession(models.AuditTokenRefresh, user, session.ID))
+
+	c.JSON(http.StatusOK, models.LoginResponse{
+		Token:            token,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     newRefreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+		User:             *user,
+	})
+}
+
+func Logout(c *gin.Context) {
+	userID := c.GetInt("user_id")
+	sessionID := c.GetInt("session_id")
+
+	if err := database.RevokeSession(sessionID, userID); err != nil && err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+		return
+	}
+
+	recordAudit(c, models.AuditLogEntry{Action: models.AuditLogout, TargetType: "session", TargetID: fmt.Sprint(sessionID)})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+func LogoutAll(c *gin.Context) {
+	userID := c.GetInt("user_id")
+
+	if err := database.RevokeAllSessions(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+		return
+	}
+
+	recordAudit(c, models.AuditLogEntry{Action: models.AuditLogoutAll, TargetType: "user", TargetID: fmt.Sprint(userID)})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out of all devices"})
+}
+
+func GetSessions(c *gin.Context) {
+	userID := c.GetInt("user_id")
+	sessionID := c.GetInt("session_id")
+
+	sessions, err := database.GetActiveSessions(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == sessionID
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+func RevokeSession(c *gin.Context) {
+	userID := c.GetInt("user_id")
+
+	var sessionID int
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &sessionID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session id"})
+		return
+	}
+
+	if err := database.RevokeSession(sessionID, userID); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+		return
+	}
+
+	recordAudit(c, models.AuditLogEntry{Action: models.AuditSessionRevoke, TargetType: "session", TargetID: fmt.Sprint(sessionID)})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseIDList parses a comma-separated list of positive ids
+func parseIDList(v string) ([]uint, error) {
+	var ids []uint
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		var id uint
+		if _, err := fmt.Sscanf(part, "%d", &id); err != nil || id == 0 || fmt.Sprint(id) != part {
+			return nil, fmt.Errorf("invalid id %q", part)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// idPlaceholders numbers one $n placeholder per id starting at argIndex and
+// returns the next free index
+func idPlaceholders(ids []uint, argIndex int) (string, []interface{}, int) {
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = fmt.Sprintf("$%d", argIndex)
+		args[i] = id
+		argIndex++
+	}
+	return strings.Join(placeholders, ", "), args, argIndex
+}
+
+// idCondition matches rows whose column is one of ids
+func idCondition(column string, ids []uint, argIndex int) (string, []interface{}, int) {
+	placeholders, args, next := idPlaceholders(ids, argIndex)
+	return fmt.Sprintf("%s IN (%s)", column, placeholders), args, next
+}
+
+// canonicalSpeciesCondition matches rows whose sourceColumn is mapped to one of
+// the canonical species through the species_crosswalk column crosswalkColumn
+// (species_id or landing_name_id)
+func canonicalSpeciesCondition(sourceColumn, crosswalkColumn string, ids []uint, argIndex int) (string, []interface{}, int) {
+	placeholders, args, next := idPlaceholders(ids, argIndex)
+	condition := fmt.Sprintf(`%s IN (
+		SELECT x.%s FROM species_crosswalk x
+		JOIN canonical_species cs ON cs.id = x.canonical_species_id
+		WHERE cs.deleted_at IS NULL AND x.canonical_species_id IN (%s))`,
+		sourceColumn, crosswalkColumn, placeholders)
+	return condition, args, next
+}
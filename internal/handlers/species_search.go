@@ -0,0 +1,327 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+	"gorm.io/gorm"
+)
+
+// Lowest trigram score kept when the query is not a plain substring of the name
+const speciesSearchMinScore = 0.3
+
+// ----------- Response Structs -----------
+
+type SpeciesSearchResult struct {
+	Source             string  `json:"source"`
+	ID                 uint    `json:"id"`
+	Name               string  `json:"name"`
+	ScientificName     *string `json:"scientific_name"`
+	MatchedOn          string  `json:"matched_on"`
+	MatchedTerm        string  `json:"matched_term"`
+	Score              float64 `json:"score"`
+	CanonicalSpeciesID *uint   `json:"canonical_species_id"`
+}
+
+// SpeciesSearchResponse groups the matched ids so they can be passed straight
+// to the species_id, landing_name_id and canonical_species_id list filters
+type SpeciesSearchResponse struct {
+	Query               string                `json:"query"`
+	Terms               []string              `json:"terms"`
+	Results             []SpeciesSearchResult `json:"results"`
+	SpeciesIDs          []uint                `json:"species_ids"`
+	LandingNameIDs      []uint                `json:"landing_name_ids"`
+	CanonicalSpeciesIDs []uint                `json:"canonical_species_ids"`
+}
+
+type SpeciesSynonymRequest struct {
+	Term    string `json:"term" binding:"required"`
+	Synonym string `json:"synonym" binding:"required"`
+}
+
+type SpeciesSynonymResponse struct {
+	ID      uint   `json:"id"`
+	Term    string `json:"term"`
+	Synonym string `json:"synonym"`
+}
+
+// ----------- Search -----------
+
+// speciesSearchStmt scores every name against each search term. Terms found
+// through a synonym rank slightly below direct matches. Each table is matched
+// with LIKE and the pg_trgm % and <% operators on its own indexed expression,
+// so the trigram indexes are used; the thresholds are set per transaction.
+const speciesSearchStmt = `
+	WITH terms AS (
+		SELECT term, MAX(weight) AS weight,
+			replace(replace(replace(term, '\', '\\'), '%', '\%'), '_', '\_') AS escaped
+		FROM (
+			SELECT $1::text AS term, 1.0::float8 AS weight
+			UNION ALL
+			SELECT synonym, 0.9 FROM species_synonyms WHERE term = $1
+			UNION ALL
+			SELECT term, 0.9 FROM species_synonyms WHERE synonym = $1
+		) t
+		GROUP BY term
+	),
+	matches AS (
+		SELECT 'species' AS source, sp.id, sp.name, NULL::varchar AS scientific_name,
+			'name' AS matched_on, LOWER(sp.name) AS value, t.term, t.weight, t.escaped
+		FROM species sp
+		JOIN terms t ON LOWER(sp.name) LIKE '%' || t.escaped || '%'
+			OR LOWER(sp.name) % t.term
+			OR t.term <% LOWER(sp.name)
+		WHERE sp.deleted_at IS NULL
+		UNION ALL
+		SELECT 'landing_name', ln.id, ln.nmfs_name, ln.scientific_name, 'name', LOWER(ln.nmfs_name), t.term, t.weight, t.escaped
+		FROM landing_names ln
+		JOIN terms t ON LOWER(ln.nmfs_name) LIKE '%' || t.escaped || '%'
+			OR LOWER(ln.nmfs_name) % t.term
+			OR t.term <% LOWER(ln.nmfs_name)
+		WHERE ln.deleted_at IS NULL
+		UNION ALL
+		SELECT 'landing_name', ln.id, ln.nmfs_name, ln.scientific_name, 'scientific_name', LOWER(ln.scientific_name), t.term, t.weight, t.escaped
+		FROM landing_names ln
+		JOIN terms t ON LOWER(ln.scientific_name) LIKE '%' || t.escaped || '%'
+			OR LOWER(ln.scientific_name) % t.term
+			OR t.term <% LOWER(ln.scientific_name)
+		WHERE ln.deleted_at IS NULL
+		UNION ALL
+		SELECT 'canonical_species', cs.id, cs.name, cs.scientific_name, 'name', LOWER(cs.name), t.term, t.weight, t.escaped
+		FROM canonical_species cs
+		JOIN terms t ON LOWER(cs.name) LIKE '%' || t.escaped || '%'
+			OR LOWER(cs.name) % t.term
+			OR t.term <% LOWER(cs.name)
+		WHERE cs.deleted_at IS NULL
+		UNION ALL
+		SELECT 'canonical_species', cs.id, cs.name, cs.scientific_name, 'scientific_name', LOWER(cs.scientific_name), t.term, t.weight, t.escaped
+		FROM canonical_species cs
+		JOIN terms t ON LOWER(cs.scientific_name) LIKE '%' || t.escaped || '%'
+			OR LOWER(cs.scientific_name) % t.term
+			OR t.term <% LOWER(cs.scientific_name)
+		WHERE cs.deleted_at IS NULL
+		UNION ALL
+		SELECT 'canonical_species', cs.id, cs.name, cs.scientific_name, 'fao_code', LOWER(cs.fao_code), t.term, t.weight, t.escaped
+		FROM canonical_species cs
+		JOIN terms t ON LOWER(cs.fao_code) LIKE '%' || t.escaped || '%'
+			OR LOWER(cs.fao_code) % t.term
+			OR t.term <% LOWER(cs.fao_code)
+		WHERE cs.deleted_at IS NULL
+	),
+	scored AS (
+		SELECT m.source, m.id, m.name, m.scientific_name, m.matched_on, m.term AS matched_term,
+			m.weight * CASE
+				WHEN m.value = m.term THEN 1.0
+				WHEN m.value LIKE m.escaped || '%' THEN 0.9
+				WHEN m.value LIKE '%' || m.escaped || '%' THEN 0.8
+				ELSE GREATEST(similarity(m.value, m.term), word_similarity(m.term, m.value))::float8 * 0.8
+			END AS score
+		FROM matches m
+	),
+	best AS (
+		SELECT DISTINCT ON (source, id) *
+		FROM scored
+		ORDER BY source, id, score DESC
+	)
+	SELECT
+		b.source, b.id, b.name, b.scientific_name, b.matched_on, b.matched_term,
+		ROUND(b.score::numeric, 3)::float8 AS score,
+		CASE b.source
+			WHEN 'canonical_species' THEN b.id
+			WHEN 'species' THEN (SELECT x.canonical_species_id FROM species_crosswalk x WHERE x.species_id = b.id)
+			ELSE (SELECT x.canonical_species_id FROM species_crosswalk x WHERE x.landing_name_id = b.id)
+		END AS canonical_species_id
+	FROM best b
+	WHERE $2::text = '' OR b.source = $2::text
+	ORDER BY b.score DESC, b.name ASC
+	LIMIT $3`
+
+// SearchSpecies ranks species, landings names and canonical species against q,
+// expanding q with its synonyms and tolerating typos through trigram similarity
+func SearchSpecies(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		q := strings.ToLower(strings.Join(strings.Fields(c.Query("q")), " "))
+		if q == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+			return
+		}
+		if len([]rune(q)) > 150 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "q must be at most 150 characters"})
+			return
+		}
+
+		source := c.Query("source")
+		switch source {
+		case "", crosswalkSourceSpecies, crosswalkSourceLandingName, "canonical_species":
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "source must be species, landing_name or canonical_species"})
+			return
+		}
+
+		limit := 20
+		if l := c.Query("limit"); l != "" {
+			fmt.Sscanf(l, "%d", &limit)
+			if limit < 1 || limit > 100 {
+				limit = 20
+			}
+		}
+
+		var results []SpeciesSearchResult
+		err := db.Transaction(func(tx *gorm.DB) error {
+			threshold := fmt.Sprint(speciesSearchMinScore)
+			if err := tx.Exec(`SELECT set_config('pg_trgm.similarity_threshold', $1, true),
+				set_config('pg_trgm.word_similarity_threshold', $1, true)`, threshold).Error; err != nil {
+				return err
+			}
+			return tx.Raw(speciesSearchStmt, q, source, limit).Scan(&results).Error
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		var terms []string
+		if err := db.Raw(`SELECT $1::text UNION SELECT synonym FROM species_synonyms WHERE term = $1
+			UNION SELECT term FROM species_synonyms WHERE synonym = $1`, q).Scan(&terms).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := SpeciesSearchResponse{
+			Query:               q,
+			Terms:               terms,
+			Results:             results,
+			SpeciesIDs:          []uint{},
+			LandingNameIDs:      []uint{},
+			CanonicalSpeciesIDs: []uint{},
+		}
+		if response.Results == nil {
+			response.Results = []SpeciesSearchResult{}
+		}
+
+		seenCanonical := map[uint]bool{}
+		for _, r := range results {
+			switch r.Source {
+			case crosswalkSourceSpecies:
+				response.SpeciesIDs = append(response.SpeciesIDs, r.ID)
+			case crosswalkSourceLandingName:
+				response.LandingNameIDs = append(response.LandingNameIDs, r.ID)
+			}
+			if r.CanonicalSpeciesID != nil && !seenCanonical[*r.CanonicalSpeciesID] {
+				seenCanonical[*r.CanonicalSpeciesID] = true
+				response.CanonicalSpeciesIDs = append(response.CanonicalSpeciesIDs, *r.CanonicalSpeciesID)
+			}
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// ----------- Synonyms -----------
+
+func AdminListSpeciesSynonyms(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := db.Model(&models.SpeciesSynonym{}).Select("id, term, synonym").Order("term ASC, synonym ASC")
+		if q := strings.ToLower(strings.TrimSpace(c.Query("q"))); q != "" {
+			pattern := "%" + escapeLike(q) + "%"
+			query = query.Where("term LIKE ? OR synonym LIKE ?", pattern, pattern)
+		}
+
+		results := []SpeciesSynonymResponse{}
+		if err := query.Scan(&results).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, results)
+	}
+}
+
+func AdminCreateSpeciesSynonym(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req SpeciesSynonymRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		term := strings.ToLower(strings.Join(strings.Fields(req.Term), " "))
+		synonym := strings.ToLower(strings.Join(strings.Fields(req.Synonym), " "))
+		if term == "" || synonym == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "term and synonym cannot be empty"})
+			return
+		}
+		if term == synonym {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "term and synonym must differ"})
+			return
+		}
+		if len([]rune(term)) > 150 || len([]rune(synonym)) > 150 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "term and synonym must be at most 150 characters"})
+			return
+		}
+
+		// Pairs work both ways, so the reversed pair is a duplicate too
+		var exists bool
+		err := db.Raw(`SELECT EXISTS (SELECT 1 FROM species_synonyms
+			WHERE (term = ? AND synonym = ?) OR (term = ? AND synonym = ?))`, term, synonym, synonym, term).
+			Scan(&exists).Error
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if exists {
+			c.JSON(http.StatusConflict, gin.H{"error": "Synonym already exists"})
+			return
+		}
+
+		row := models.SpeciesSynonym{Term: term, Synonym: synonym}
+		if err := db.Create(&row).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		recordAudit(c, models.AuditLogEntry{
+			Action:     models.AuditCatalogCreate,
+			TargetType: "species_synonyms",
+			TargetID:   fmt.Sprint(row.ID),
+			Details:    fmt.Sprintf("%s=%s", term, synonym),
+		})
+		c.JSON(http.StatusCreated, SpeciesSynonymResponse{ID: row.ID, Term: row.Term, Synonym: row.Synonym})
+	}
+}
+
+func AdminDeleteSpeciesSynonym(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCatalogID(c)
+		if !ok {
+			return
+		}
+
+		res := db.Exec(`DELETE FROM species_synonyms WHERE id = ?`, id)
+		if res.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": res.Error.Error()})
+			return
+		}
+		if res.RowsAffected == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Synonym not found"})
+			return
+		}
+
+		recordAudit(c, models.AuditLogEntry{
+			Action:     models.AuditCatalogDelete,
+			TargetType: "species_synonyms",
+			TargetID:   fmt.Sprint(id),
+		})
+		c.JSON(http.StatusOK, gin.H{"message": "Synonym deleted"})
+	}
+}
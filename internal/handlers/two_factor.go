@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/database"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/utils"
+)
+
+const recoveryCodeCount = 10
+
+// generateRecoveryCodes creates a new set of one-time recovery codes for the
+// user, replacing any previous set, and returns them in plain text
+func generateRecoveryCodes(userID int) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		raw, err := utils.GenerateRandomToken(5)
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = utils.HashToken(normalizeRecoveryCode(codes[i]))
+	}
+
+	if err := database.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// verifySecondFactor accepts either a current TOTP code or an unused recovery code
+func verifySecondFactor(userID int, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	secret, lastStep, err := database.GetTOTPSecret(userID)
+	if err != nil {
+		return false, err
+	}
+
+	if len(code) == utils.TOTPDigits {
+		step, ok := utils.ValidateTOTP(secret, code, time.Now(), lastStep)
+		if !ok {
+			return false, nil
+		}
+		return database.UseTOTPStep(userID, step)
+	}
+
+	return database.UseRecoveryCode(userID, utils.HashToken(normalizeRecoveryCode(code)))
+}
+
+// SetupTwoFactor starts enrollment by generating a secret that must be confirmed with a code
+func SetupTwoFactor(c *gin.Context) {
+	userID := c.GetInt("user_id")
+
+	user, err := database.GetUserByID(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if user.TOTPEnabledAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is already enabled"})
+		return
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate secret"})
+		return
+	}
+
+	if err := database.SetPendingTOTPSecret(userID, secret); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save secret"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPURI(utils.TOTPIssuer(), user.Email, secret),
+	})
+}
+
+// ConfirmTwoFactor enables 2FA once the user proves their authenticator works,
+// and returns the recovery codes
+func ConfirmTwoFactor(c *gin.Context) {
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt("user_id")
+
+	user, err := database.GetUserByID(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if user.TOTPEnabledAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is already enabled"})
+		return
+	}
+
+	secret, lastStep, err := database.GetTOTPSecret(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor setup has not been started"})
+		return
+	}
+
+	step, ok := utils.ValidateTOTP(secret, strings.TrimSpace(req.Code), time.Now(), lastStep)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid code"})
+		return
+	}
+	if _, err := database.UseTOTPStep(userID, step); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+
+	codes, err := generateRecoveryCodes(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate recovery codes"})
+		return
+	}
+
+	if err := database.EnableTOTP(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enable two-factor authentication"})
+		return
+	}
+
+	recordAudit(c, auditUser(models.Audit2FAEnable, user))
+
+	c.JSON(http.StatusOK, models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTwoFactor turns 2FA off after checking the password and a second factor
+func DisableTwoFactor(c *gin.Context) {
+	var req models.TwoFactorDisableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt("user_id")
+
+	user, err := database.GetUserByID(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if user.TOTPEnabledAt == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is not enabled"})
+		return
+	}
+
+	if !user.CheckPassword(req.Password) {
+		recordAudit(c, auditUserFailure(models.Audit2FADisable, user, "invalid password"))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+		return
+	}
+
+	ok, err := verifySecondFactor(userID, req.Code)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if !ok {
+		recordAudit(c, auditUserFailure(models.Audit2FADisable, user, "invalid code"))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid code"})
+		return
+	}
+
+	if err := database.DisableTOTP(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to disable two-factor authentication"})
+		return
+	}
+
+	recordAudit(c, auditUser(models.Audit2FADisable, user))
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes after checking a second factor
+func RegenerateRecoveryCodes(c *gin.Context) {
+	var req models.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt("user_id")
+
+	user, err := database.GetUserByID(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if user.TOTPEnabledAt == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is not enabled"})
+		return
+	}
+
+	ok, err := verifySecondFactor(userID, req.Code)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid code"})
+		return
+	}
+
+	codes, err := generateRecoveryCodes(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate recovery codes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// LoginTwoFactor completes a login for an account with 2FA by exchanging the
+// challenge token from Login and a code for a session
+func LoginTwoFactor(c *gin.Context) {
+	var req models.LoginTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Throttle clients that keep failing
+	if ipLoginBlocked(c) {
+		recordAudit(c, auditFailure(models.AuditLogin2FA, "", "too many failed attempts from this IP"))
+		return
+	}
+
+	claims, err := utils.ValidateChallengeToken(req.ChallengeToken)
+	if err != nil {
+		recordAudit(c, auditFailure(models.AuditLogin2FA, "", "invalid challenge token"))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge token"})
+		return
+	}
+
+	user, err := database.GetUserByID(claims.UserID)
+	if err != nil || user.TOTPEnabledAt == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge token"})
+		return
+	}
+
+	// A challenge from before a password change is stale
+	if user.PasswordChangedAt != nil && claims.IssuedAt != nil &&
+		claims.IssuedAt.Time.Before(user.PasswordChangedAt.Truncate(time.Second)) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge token"})
+		return
+	}
+
+	if accountLocked(c, user) {
+		recordAudit(c, auditUserFailure(models.AuditLogin2FA, user, "account locked"))
+		return
+	}
+
+	ok, err := verifySecondFactor(user.ID, req.Code)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	if !ok {
+		recordAudit(c, auditUserFailure(models.AuditLogin2FA, user, "invalid code"))
+		recordFailedLogin(c, user.Email, user)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid code"})
+		return
+	}
+
+	recordSuccessfulLogin(c, user)
+
+	// Start a session and generate tokens
+	response, err := issueSession(c, user, req.DeviceName)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	recordAudit(c, auditUser(models.AuditLogin2FA, user))
+
+	c.JSON(http.StatusOK, response)
+}
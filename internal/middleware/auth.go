@@ -3,13 +3,27 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/database"
 	"github.com/manjunath-tintbytes/seafoodai.api/internal/utils"
 )
 
+// Values stored under "auth_method"
+const (
+	AuthMethodJWT    = "jwt"
+	AuthMethodAPIKey = "api_key"
+)
+
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// API keys may come in X-API-Key or as "Authorization: ApiKey <key>"
+		if key := apiKeyFromRequest(c); key != "" {
+			authenticateAPIKey(c, key)
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing authorization header"})
@@ -31,8 +45,83 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// The token's session must still be live
+		session, err := database.GetSessionByID(claims.SessionID)
+		if err != nil || session.UserID != claims.UserID || session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has expired or been revoked"})
+			c.Abort()
+			return
+		}
+
+		// Tokens issued before the last password change are no longer valid
+		user, err := database.GetUserByID(claims.UserID)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.Abort()
+			return
+		}
+		if user.PasswordChangedAt != nil && claims.IssuedAt != nil &&
+			claims.IssuedAt.Time.Before(user.PasswordChangedAt.Truncate(time.Second)) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token issued before password change"})
+			c.Abort()
+			return
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
+		c.Set("session_id", claims.SessionID)
+		c.Set("auth_method", AuthMethodJWT)
+		// The stored role wins over the claim so role changes apply immediately
+		c.Set("role", user.Role)
+		c.Set("email_verified", user.EmailVerifiedAt != nil)
 		c.Next()
 	}
 }
+
+func apiKeyFromRequest(c *gin.Context) string {
+	if key := strings.TrimSpace(c.GetHeader("X-API-Key")); key != "" {
+		return key
+	}
+	parts := strings.Split(c.GetHeader("Authorization"), " ")
+	if len(parts) == 2 && parts[0] == "ApiKey" {
+		return parts[1]
+	}
+	return ""
+}
+
+func authenticateAPIKey(c *gin.Context, key string) {
+	apiKey, err := database.GetAPIKeyByHash(utils.HashToken(key))
+	if err != nil || apiKey.RevokedAt != nil || (apiKey.ExpiresAt != nil && time.Now().After(*apiKey.ExpiresAt)) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		c.Abort()
+		return
+	}
+
+	user, err := database.GetUserByID(apiKey.UserID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		c.Abort()
+		return
+	}
+
+	// Keys shared with an organization stop working when their creator leaves it
+	if apiKey.OrganizationID != nil {
+		if _, err := database.GetMemberRole(*apiKey.OrganizationID, apiKey.UserID); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+			c.Abort()
+			return
+		}
+	}
+
+	if err := database.TouchAPIKey(apiKey.ID); err != nil {
+		c.Error(err)
+	}
+
+	c.Set("user_id", user.ID)
+	c.Set("email", user.Email)
+	c.Set("auth_method", AuthMethodAPIKey)
+	c.Set("api_key", apiKey)
+	c.Set("role", user.Role)
+	c.Set("email_verified", user.EmailVerifiedAt != nil)
+	c.Next()
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+)
+
+// RequireRole only lets through users whose role is at least the given role.
+// It must run after AuthMiddleware, which sets the caller's role.
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !models.HasRole(c.GetString("role"), role) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
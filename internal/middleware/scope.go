@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
+)
+
+// RequireScope restricts API key callers to keys carrying the given scope.
+// Callers signed in with a JWT are not affected.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("auth_method") != AuthMethodAPIKey {
+			c.Next()
+			return
+		}
+		value, _ := c.Get("api_key")
+		apiKey, ok := value.(*models.APIKey)
+		if !ok || !apiKey.HasScope(scope) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "API key is missing the " + scope + " scope"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireInteractive rejects API key callers, for routes that manage credentials
+func RequireInteractive() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("auth_method") == AuthMethodAPIKey {
+			c.JSON(http.StatusForbidden, gin.H{"error": "This endpoint requires a signed-in user"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/config"
+)
+
+// RequireVerifiedEmail blocks accounts that have not confirmed their email
+// address. It only applies when REQUIRE_EMAIL_VERIFICATION is enabled.
+func RequireVerifiedEmail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if config.RequireEmailVerification() && !c.GetBool("email_verified") {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Email address has not been verified"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
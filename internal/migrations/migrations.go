@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-gormigrate/gormigrate/v2"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GetMigrations returns all migrations
@@ -108,5 +109,189 @@ func GetMigrations() []*gormigrate.Migration {
 				return nil
 			},
 		},
+		{
+			ID: "202510200001_add_price_currency_and_exchange_rates",
+			Migrate: func(tx *gorm.DB) error {
+				// Add currency column to prices (existing rows are EUR)
+				if err := tx.AutoMigrate(&models.Price{}); err != nil {
+					return err
+				}
+
+				// Create exchange_rates table
+				if err := tx.AutoMigrate(&models.ExchangeRate{}); err != nil {
+					return err
+				}
+
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(&models.ExchangeRate{}); err != nil {
+					return err
+				}
+				if err := tx.Migrator().DropColumn(&models.Price{}, "Currency"); err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			ID: "202510200002_add_seafood_piece_weight",
+			Migrate: func(tx *gorm.DB) error {
+				// Add piece_weight_kg column to seafoods
+				return tx.AutoMigrate(&models.Seafood{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&models.Seafood{}, "PieceWeightKg")
+			},
+		},
+		{
+			ID: "202510200003_extend_market_signals",
+			Migrate: func(tx *gorm.DB) error {
+				// Add summary, body and source columns to market_signals
+				if err := tx.AutoMigrate(&models.MarketSignal{}); err != nil {
+					return err
+				}
+
+				// Create market_signal_tags table
+				if err := tx.AutoMigrate(&models.MarketSignalTag{}); err != nil {
+					return err
+				}
+
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(&models.MarketSignalTag{}); err != nil {
+					return err
+				}
+				for _, column := range []string{"Summary", "Body", "SourceURL", "SourceName"} {
+					if err := tx.Migrator().DropColumn(&models.MarketSignal{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ID: "202510200004_link_market_signals",
+			Migrate: func(tx *gorm.DB) error {
+				// Create market_signal_species, market_signal_regions and market_signal_landing_names join tables
+				return tx.AutoMigrate(&models.MarketSignal{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("market_signal_landing_names", "market_signal_regions", "market_signal_species")
+			},
+		},
+		{
+			ID: "202510200005_create_price_revisions",
+			Migrate: func(tx *gorm.DB) error {
+				// Create price_revisions table
+				return tx.AutoMigrate(&models.PriceRevision{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&models.PriceRevision{})
+			},
+		},
+		{
+			ID: "202510200006_create_species_crosswalk",
+			Migrate: func(tx *gorm.DB) error {
+				// Create canonical_species table
+				if err := tx.AutoMigrate(&models.CanonicalSpecies{}); err != nil {
+					return err
+				}
+
+				// Create species_crosswalk table
+				if err := tx.AutoMigrate(&models.SpeciesCrosswalk{}); err != nil {
+					return err
+				}
+
+				// Each mapping has exactly one source
+				return tx.Exec(`ALTER TABLE species_crosswalk ADD CONSTRAINT chk_species_crosswalk_source
+					CHECK ((species_id IS NULL) <> (landing_name_id IS NULL))`).Error
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(&models.SpeciesCrosswalk{}); err != nil {
+					return err
+				}
+				return tx.Migrator().DropTable(&models.CanonicalSpecies{})
+			},
+		},
+		{
+			ID: "202510200007_add_species_search",
+			Migrate: func(tx *gorm.DB) error {
+				// Enable trigram matching
+				if err := tx.Exec(`CREATE EXTENSION IF NOT EXISTS pg_trgm`).Error; err != nil {
+					return err
+				}
+
+				// Create species_synonyms table
+				if err := tx.AutoMigrate(&models.SpeciesSynonym{}); err != nil {
+					return err
+				}
+
+				// Trigram indexes on the searched names
+				for _, stmt := range []string{
+					`CREATE INDEX IF NOT EXISTS idx_species_name_trgm ON species USING gin (LOWER(name) gin_trgm_ops)`,
+					`CREATE INDEX IF NOT EXISTS idx_landing_names_nmfs_name_trgm ON landing_names USING gin (LOWER(nmfs_name) gin_trgm_ops)`,
+					`CREATE INDEX IF NOT EXISTS idx_landing_names_scientific_name_trgm ON landing_names USING gin (LOWER(scientific_name) gin_trgm_ops)`,
+					`CREATE INDEX IF NOT EXISTS idx_canonical_species_name_trgm ON canonical_species USING gin (LOWER(name) gin_trgm_ops)`,
+				} {
+					if err := tx.Exec(stmt).Error; err != nil {
+						return err
+					}
+				}
+
+				// Common trade and regional names
+				synonyms := []models.SpeciesSynonym{
+					{Term: "prawn", Synonym: "shrimp"},
+					{Term: "langoustine", Synonym: "norway lobster"},
+					{Term: "scampi", Synonym: "norway lobster"},
+					{Term: "coley", Synonym: "saithe"},
+					{Term: "calamari", Synonym: "squid"},
+					{Term: "monkfish", Synonym: "anglerfish"},
+					{Term: "crawfish", Synonym: "crayfish"},
+					{Term: "dolphinfish", Synonym: "mahi mahi"},
+				}
+				return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&synonyms).Error
+			},
+			Rollback: func(tx *gorm.DB) error {
+				for _, index := range []string{
+					"idx_species_name_trgm",
+					"idx_landing_names_nmfs_name_trgm",
+					"idx_landing_names_scientific_name_trgm",
+					"idx_canonical_species_name_trgm",
+				} {
+					if err := tx.Exec(`DROP INDEX IF EXISTS ` + index).Error; err != nil {
+						return err
+					}
+				}
+				return tx.Migrator().DropTable(&models.SpeciesSynonym{})
+			},
+		},
+		{
+			ID: "202510200008_add_canonical_species_search_indexes",
+			Migrate: func(tx *gorm.DB) error {
+				// Trigram indexes for the remaining names species search matches on
+				for _, stmt := range []string{
+					`CREATE INDEX IF NOT EXISTS idx_canonical_species_scientific_name_trgm ON canonical_species USING gin (LOWER(scientific_name) gin_trgm_ops)`,
+					`CREATE INDEX IF NOT EXISTS idx_canonical_species_fao_code_trgm ON canonical_species USING gin (LOWER(fao_code) gin_trgm_ops)`,
+				} {
+					if err := tx.Exec(stmt).Error; err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				for _, index := range []string{
+					"idx_canonical_species_scientific_name_trgm",
+					"idx_canonical_species_fao_code_trgm",
+				} {
+					if err := tx.Exec(`DROP INDEX IF EXISTS ` + index).Error; err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
 	}
 }
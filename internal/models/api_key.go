@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Scopes an API key can be restricted to. A key without scopes may reach every
+// route its owner's role allows, except the admin routes.
+const (
+	ScopeMarketPrices  = "market-prices"
+	ScopeLandings      = "landings"
+	ScopeMarketSignals = "market-signals"
+	ScopeQuotas        = "quotas"
+	ScopeSpecies       = "species"
+	ScopeAdmin         = "admin"
+)
+
+var validScopes = map[string]bool{
+	ScopeMarketPrices:  true,
+	ScopeLandings:      true,
+	ScopeMarketSignals: true,
+	ScopeQuotas:        true,
+	ScopeSpecies:       true,
+	ScopeAdmin:         true,
+}
+
+// ValidScope reports whether scope is one of the known API key scopes
+func ValidScope(scope string) bool {
+	return validScopes[scope]
+}
+
+type APIKey struct {
+	ID             int        `json:"id"`
+	UserID         int        `json:"user_id"`
+	OrganizationID *int       `json:"organization_id"`
+	Name           string     `json:"name"`
+	Prefix         string     `json:"prefix"`
+	KeyHash        string     `json:"-"`
+	Scopes         []string   `json:"scopes"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+}
+
+// HasScope reports whether the key may be used for routes guarded by scope
+func (k *APIKey) HasScope(scope string) bool {
+	if len(k.Scopes) == 0 {
+		return scope != ScopeAdmin
+	}
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type CreateAPIKeyRequest struct {
+	Name           string     `json:"name" binding:"required,max=100"`
+	Scopes         []string   `json:"scopes"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	OrganizationID *int       `json:"organization_id"`
+}
+
+type CreateAPIKeyResponse struct {
+	Key    string `json:"key"`
+	APIKey APIKey `json:"api_key"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// Audited actions
+const (
+	AuditSignup               = "signup"
+	AuditLogin                = "login"
+	AuditLogin2FA             = "login_2fa"
+	AuditLoginOIDC            = "login_oidc"
+	AuditLogout               = "logout"
+	AuditLogoutAll            = "logout_all"
+	AuditTokenRefresh         = "token_refresh"
+	AuditSessionRevoke        = "session_revoke"
+	AuditPasswordResetRequest = "password_reset_request"
+	AuditPasswordReset        = "password_reset"
+	AuditPasswordChange       = "password_change"
+	AuditEmailVerify          = "email_verify"
+	AuditEmailVerifyResend    = "email_verify_resend"
+	AuditAccountLock          = "account_lock"
+	AuditAccountUnlock        = "account_unlock"
+	AuditAccountDelete        = "account_delete"
+	AuditProfileUpdate        = "profile_update"
+	Audit2FAEnable            = "2fa_enable"
+	Audit2FADisable           = "2fa_disable"
+	AuditAPIKeyCreate         = "api_key_create"
+	AuditAPIKeyRevoke         = "api_key_revoke"
+	AuditUserRoleUpdate       = "user_role_update"
+	AuditCatalogCreate        = "catalog_create"
+	AuditCatalogUpdate        = "catalog_update"
+	AuditCatalogDelete        = "catalog_delete"
+	AuditCatalogRestore       = "catalog_restore"
+	AuditPriceCreate          = "price_create"
+	AuditPriceCorrect         = "price_correct"
+)
+
+// Audit outcomes
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+type AuditLogEntry struct {
+	ID          int64     `json:"id"`
+	ActorUserID *int      `json:"actor_user_id"`
+	ActorEmail  string    `json:"actor_email"`
+	Action      string    `json:"action"`
+	TargetType  string    `json:"target_type"`
+	TargetID    string    `json:"target_id"`
+	IPAddress   string    `json:"ip_address"`
+	UserAgent   string    `json:"user_agent"`
+	Outcome     string    `json:"outcome"`
+	Details     string    `json:"details"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AuditLogFilter narrows an audit log listing; zero values are ignored
+type AuditLogFilter struct {
+	ActorUserID *int
+	ActorEmail  string
+	Action      string
+	Outcome     string
+	TargetType  string
+	TargetID    string
+	IPAddress   string
+	From        *time.Time
+	To          *time.Time
+}
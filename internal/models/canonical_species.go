@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// CanonicalSpecies is the shared identity that market-price species and
+// landings names are mapped to through SpeciesCrosswalk
+type CanonicalSpecies struct {
+	ID             uint    `gorm:"primaryKey"`
+	Name           string  `gorm:"type:varchar(150);not null;index"`
+	ScientificName string  `gorm:"type:varchar(150)"`
+	FAOCode        *string `gorm:"type:varchar(3);uniqueIndex"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      *time.Time `gorm:"index"`
+}
+
+// SpeciesCrosswalk maps exactly one of a Species or a LandingName to a
+// canonical species. Each source row maps to at most one canonical species.
+type SpeciesCrosswalk struct {
+	ID                 uint  `gorm:"primaryKey"`
+	CanonicalSpeciesID uint  `gorm:"not null;index"`
+	SpeciesID          *uint `gorm:"uniqueIndex"`
+	LandingNameID      *uint `gorm:"uniqueIndex"`
+	CreatedAt          time.Time
+
+	// Associations
+	CanonicalSpecies CanonicalSpecies `gorm:"foreignKey:CanonicalSpeciesID;constraint:OnDelete:CASCADE"`
+	Species          *Species         `gorm:"foreignKey:SpeciesID;constraint:OnDelete:CASCADE"`
+	LandingName      *LandingName     `gorm:"foreignKey:LandingNameID;constraint:OnDelete:CASCADE"`
+}
+
+func (SpeciesCrosswalk) TableName() string {
+	return "species_crosswalk"
+}
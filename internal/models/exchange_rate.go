@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// ExchangeRate stores a dated conversion rate where 1 BaseCurrency = Rate QuoteCurrency
+type ExchangeRate struct {
+	ID            uint      `gorm:"primaryKey"`
+	Date          time.Time `gorm:"type:date;not null;uniqueIndex:idx_rate_date_pair"`
+	BaseCurrency  string    `gorm:"type:varchar(3);not null;uniqueIndex:idx_rate_date_pair"`
+	QuoteCurrency string    `gorm:"type:varchar(3);not null;uniqueIndex:idx_rate_date_pair"`
+	Rate          float64   `gorm:"type:numeric(18,8);not null"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     *time.Time `gorm:"index"`
+}
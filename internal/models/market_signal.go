@@ -5,10 +5,18 @@ import (
 )
 
 type MarketSignal struct {
-	ID            uint      `gorm:"primaryKey" json:"id"`
-	Title         string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_title_published" json:"title"`
-	PublishedDate time.Time `gorm:"uniqueIndex:idx_title_published" json:"published_date"`
-	Author        string    `gorm:"type:varchar(255)" json:"author"`
+	ID            uint              `gorm:"primaryKey" json:"id"`
+	Title         string            `gorm:"type:varchar(255);not null;uniqueIndex:idx_title_published" json:"title"`
+	PublishedDate time.Time         `gorm:"uniqueIndex:idx_title_published" json:"published_date"`
+	Author        string            `gorm:"type:varchar(255)" json:"author"`
+	Summary       string            `gorm:"type:text" json:"summary"`
+	Body          string            `gorm:"type:text" json:"body"`
+	SourceURL     string            `gorm:"type:varchar(1024)" json:"source_url"`
+	SourceName    string            `gorm:"type:varchar(255)" json:"source_name"`
+	Tags          []MarketSignalTag `gorm:"foreignKey:MarketSignalID" json:"tags,omitempty"`
+	Species       []Species         `gorm:"many2many:market_signal_species" json:"species,omitempty"`
+	Regions       []Region          `gorm:"many2many:market_signal_regions" json:"regions,omitempty"`
+	LandingNames  []LandingName     `gorm:"many2many:market_signal_landing_names" json:"landing_names,omitempty"`
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 	DeletedAt     *time.Time `gorm:"index"`
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+type MarketSignalTag struct {
+	ID             uint   `gorm:"primaryKey" json:"id"`
+	MarketSignalID uint   `gorm:"not null;uniqueIndex:idx_signal_tag" json:"market_signal_id"`
+	Tag            string `gorm:"type:varchar(100);not null;uniqueIndex:idx_signal_tag;index" json:"tag"`
+	CreatedAt      time.Time
+}
+
+// NormalizeTag lower-cases and trims a tag so filters match regardless of source casing
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
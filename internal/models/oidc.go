@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// OIDCLoginState is the server-side half of a pending OIDC login, keyed by the
+// hash of the state parameter sent to the provider
+type OIDCLoginState struct {
+	ID           int       `json:"id"`
+	StateHash    string    `json:"-"`
+	Provider     string    `json:"provider"`
+	Nonce        string    `json:"-"`
+	CodeVerifier string    `json:"-"`
+	IPAddress    string    `json:"-"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type OIDCLoginResponse struct {
+	AuthorizationURL string    `json:"authorization_url"`
+	State            string    `json:"state"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+type OIDCCallbackRequest struct {
+	Code       string `json:"code" binding:"required"`
+	State      string `json:"state" binding:"required"`
+	DeviceName string `json:"device_name"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Roles within an organization
+const (
+	OrgRoleOwner  = "owner"
+	OrgRoleMember = "member"
+)
+
+// Organization groups users so they can share work. API keys are the only
+// resource users create today, so they are the only thing scoped to an
+// organization (api_keys.organization_id); catalog data, prices and price
+// corrections are global and managed by admins. New user-owned resources
+// should carry an organization_id the same way.
+type Organization struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedBy *int      `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	Role      string    `json:"role,omitempty"`
+}
+
+type OrganizationMember struct {
+	UserID   int       `json:"user_id"`
+	Email    string    `json:"email"`
+	Name     string    `json:"name"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type OrganizationDetail struct {
+	Organization
+	Members []OrganizationMember `json:"members"`
+}
+
+type OrganizationInvite struct {
+	ID             int        `json:"id"`
+	OrganizationID int        `json:"organization_id"`
+	Email          string     `json:"email"`
+	Role           string     `json:"role"`
+	TokenHash      string     `json:"-"`
+	InvitedBy      *int       `json:"invited_by"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type OrganizationRequest struct {
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+type CreateInviteRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"omitempty,oneof=owner member"`
+}
+
+type AcceptInviteRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=owner member"`
+}
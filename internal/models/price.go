@@ -7,6 +7,7 @@ type Price struct {
 	SeafoodID uint      `gorm:"not null;index"`
 	Date      time.Time `gorm:"default:now();index"`
 	Price     float64   `gorm:"type:numeric(12,2);not null"`
+	Currency  string    `gorm:"type:varchar(3);not null;default:'EUR'"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time `gorm:"index"`
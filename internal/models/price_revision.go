@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// PriceRevision keeps the values a price had before a correction, with who made
+// the correction and why. RevisedByUserID has no foreign key so revisions
+// outlive deleted users.
+type PriceRevision struct {
+	ID              uint      `gorm:"primaryKey"`
+	PriceID         uint      `gorm:"not null;index"`
+	SeafoodID       uint      `gorm:"not null"`
+	Date            time.Time `gorm:"not null"`
+	Price           float64   `gorm:"type:numeric(12,2);not null"`
+	Currency        string    `gorm:"type:varchar(3);not null"`
+	Reason          string    `gorm:"type:text;not null"`
+	RevisedByUserID *int
+	RevisedByEmail  string `gorm:"type:varchar(255)"`
+	CreatedAt       time.Time
+}
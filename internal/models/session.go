@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type Session struct {
+	ID               int        `json:"id"`
+	UserID           int        `json:"user_id"`
+	RefreshTokenHash string     `json:"-"`
+	DeviceName       string     `json:"device_name"`
+	UserAgent        string     `json:"user_agent"`
+	IPAddress        string     `json:"ip_address"`
+	CreatedAt        time.Time  `json:"created_at"`
+	LastUsedAt       time.Time  `json:"last_used_at"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	Current          bool       `json:"current"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// SpeciesSynonym makes species search treat Term and Synonym as the same word,
+// in both directions. Both are stored lower-cased.
+type SpeciesSynonym struct {
+	ID        uint   `gorm:"primaryKey"`
+	Term      string `gorm:"type:varchar(150);not null;uniqueIndex:idx_species_synonym_pair"`
+	Synonym   string `gorm:"type:varchar(150);not null;uniqueIndex:idx_species_synonym_pair;index"`
+	CreatedAt time.Time
+}
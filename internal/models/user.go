@@ -7,27 +7,66 @@ import (
 )
 
 type User struct {
-	ID        int       `json:"id"`
-	Email     string    `json:"email"`
-	Password  string    `json:"-"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
+	ID                int        `json:"id"`
+	Email             string     `json:"email"`
+	PendingEmail      *string    `json:"pending_email,omitempty"`
+	Password          string     `json:"-"`
+	Name              string     `json:"name"`
+	Role              string     `json:"role"`
+	EmailVerifiedAt   *time.Time `json:"email_verified_at"`
+	TOTPEnabledAt     *time.Time `json:"totp_enabled_at"`
+	LockedUntil       *time.Time `json:"locked_until"`
+	CreatedAt         time.Time  `json:"created_at"`
+	PasswordChangedAt *time.Time `json:"-"`
+}
+
+// Roles in increasing order of privilege
+const (
+	RoleViewer  = "viewer"
+	RoleAnalyst = "analyst"
+	RoleAdmin   = "admin"
+)
+
+var roleRank = map[string]int{
+	RoleViewer:  1,
+	RoleAnalyst: 2,
+	RoleAdmin:   3,
+}
+
+// ValidRole reports whether role is one of the known roles
+func ValidRole(role string) bool {
+	_, ok := roleRank[role]
+	return ok
+}
+
+// HasRole reports whether role grants at least the privileges of required
+func HasRole(role, required string) bool {
+	return ValidRole(role) && roleRank[role] >= roleRank[required]
+}
+
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=viewer analyst admin"`
 }
 
 type SignupRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
-	Name     string `json:"name" binding:"required"`
+	Email      string `json:"email" binding:"required,email"`
+	Password   string `json:"password" binding:"required,min=6"`
+	Name       string `json:"name" binding:"required"`
+	DeviceName string `json:"device_name"`
 }
 
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
+	Email      string `json:"email" binding:"required,email"`
+	Password   string `json:"password" binding:"required"`
+	DeviceName string `json:"device_name"`
 }
 
 type LoginResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	Token            string    `json:"token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+	User             User      `json:"user"`
 }
 
 func (u *User) HashPassword(password string) error {
@@ -53,10 +92,60 @@ type ResetPasswordRequest struct {
 	Password string `json:"password" binding:"required,min=6"`
 }
 
+type UpdateProfileRequest struct {
+	Name            *string `json:"name" binding:"omitempty,min=1"`
+	Email           *string `json:"email" binding:"omitempty,email"`
+	CurrentPassword string  `json:"current_password"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+	DeviceName      string `json:"device_name"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type EmailVerificationToken struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Token     string    `json:"token"`
+	Email     string    `json:"email"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Used      bool      `json:"used"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type UnlockAccountRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type AccountUnlockToken struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Token     string    `json:"token"`
+	Email     string    `json:"email"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Used      bool      `json:"used"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type PasswordResetToken struct {
 	ID        int       `json:"id"`
 	UserID    int       `json:"user_id"`
 	Token     string    `json:"token"`
+	Email     string    `json:"email"`
 	ExpiresAt time.Time `json:"expires_at"`
 	Used      bool      `json:"used"`
 	CreatedAt time.Time `json:"created_at"`
@@ -0,0 +1,179 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// IDTokenClaims are the ID token claims used to find or provision a user
+type IDTokenClaims struct {
+	Email           string  `json:"email"`
+	EmailVerified   boolish `json:"email_verified"`
+	Name            string  `json:"name"`
+	Nonce           string  `json:"nonce"`
+	AuthorizedParty string  `json:"azp"`
+	jwt.RegisteredClaims
+}
+
+// boolish accepts both true and "true", since some providers send email_verified as a string
+type boolish bool
+
+func (b *boolish) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch t := v.(type) {
+	case bool:
+		*b = boolish(t)
+	case string:
+		parsed, _ := strconv.ParseBool(t)
+		*b = boolish(parsed)
+	}
+	return nil
+}
+
+// VerifyIDToken checks the ID token signature against the provider's JWKS and
+// validates issuer, audience, expiry and nonce
+func (p *Provider) VerifyIDToken(ctx context.Context, rawToken, nonce string) (*IDTokenClaims, error) {
+	d, err := p.loadDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &IDTokenClaims{}
+	_, err = jwt.ParseWithClaims(rawToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.signingKey(ctx, d.JWKSURI, kid)
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithIssuer(p.Issuer),
+		jwt.WithAudience(p.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("id token: %w", err)
+	}
+
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.ClientID {
+		return nil, errors.New("id token: azp does not match client id")
+	}
+	if claims.Nonce != nonce {
+		return nil, errors.New("id token: nonce mismatch")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("id token: missing sub")
+	}
+	return claims, nil
+}
+
+// ----------- JWKS -----------
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type keySet struct {
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// jwksRefreshInterval limits how often an unknown key id triggers a refetch
+const jwksRefreshInterval = time.Minute
+
+// signingKey returns the RSA key for kid, refetching the JWKS once when the
+// key is unknown so provider key rotation is picked up
+func (p *Provider) signingKey(ctx context.Context, jwksURI, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.keys != nil {
+		if key, ok := p.keys.lookup(kid); ok {
+			return key, nil
+		}
+		if time.Since(p.keys.fetchedAt) < jwksRefreshInterval {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+	}
+
+	keys, err := fetchKeySet(ctx, jwksURI)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+
+	if key, ok := p.keys.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookup finds the key by id. A token without a kid matches only when the set holds a single key.
+func (s *keySet) lookup(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" {
+		if len(s.keys) == 1 {
+			for _, key := range s.keys {
+				return key, true
+			}
+		}
+		return nil, false
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+func fetchKeySet(ctx context.Context, jwksURI string) (*keySet, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := getJSON(ctx, jwksURI, &doc); err != nil {
+		return nil, fmt.Errorf("jwks: %w", err)
+	}
+
+	set := &keySet{keys: make(map[string]*rsa.PublicKey), fetchedAt: time.Now()}
+	for _, k := range doc.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		key, err := k.rsaPublicKey()
+		if err != nil {
+			continue
+		}
+		set.keys[k.Kid] = key
+	}
+	return set, nil
+}
+
+func (k jwk) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+	if len(n) == 0 || len(e) == 0 || len(e) > 4 {
+		return nil, errors.New("invalid RSA key")
+	}
+
+	exponent := 0
+	for _, b := range e {
+		exponent = exponent<<8 | int(b)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}, nil
+}
@@ -0,0 +1,192 @@
+// Package oidc implements the relying-party side of the OpenID Connect
+// authorization code flow with PKCE, using only the standard library and
+// golang-jwt for ID token verification.
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/config"
+)
+
+var (
+	ErrUnknownProvider = errors.New("unknown identity provider")
+
+	httpClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+// discovery holds the fields of /.well-known/openid-configuration we use
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider is a configured identity provider. Its discovery document and
+// signing keys are fetched on first use and cached.
+type Provider struct {
+	config.OIDCProviderConfig
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      *keySet
+}
+
+var (
+	providersOnce sync.Once
+	providers     map[string]*Provider
+)
+
+// GetProvider returns the provider configured under name
+func GetProvider(name string) (*Provider, error) {
+	providersOnce.Do(func() {
+		providers = make(map[string]*Provider)
+		for _, cfg := range config.OIDCProviders() {
+			providers[cfg.Name] = &Provider{OIDCProviderConfig: cfg}
+		}
+	})
+	p, ok := providers[strings.ToLower(name)]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	return p, nil
+}
+
+func (p *Provider) loadDiscovery(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	wellKnown := strings.TrimSuffix(p.Issuer, "/") + "/.well-known/openid-configuration"
+	var d discovery
+	if err := getJSON(ctx, wellKnown, &d); err != nil {
+		return nil, fmt.Errorf("discovery: %w", err)
+	}
+	if d.Issuer != p.Issuer {
+		return nil, fmt.Errorf("discovery: issuer %q does not match configured issuer %q", d.Issuer, p.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, errors.New("discovery: document is missing required endpoints")
+	}
+	p.discovery = &d
+	return p.discovery, nil
+}
+
+// AuthCodeURL builds the authorization request URL for the given state,
+// nonce and PKCE verifier
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	d, err := p.loadDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.ClientID)
+	params.Set("redirect_uri", p.RedirectURL)
+	params.Set("scope", strings.Join(p.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", CodeChallenge(codeVerifier))
+	params.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return d.AuthorizationEndpoint + sep + params.Encode(), nil
+}
+
+// CodeChallenge derives the S256 PKCE challenge from a verifier (RFC 7636)
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Exchange redeems an authorization code and returns the verified ID token claims
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*IDTokenClaims, error) {
+	d, err := p.loadDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.RedirectURL)
+	form.Set("client_id", p.ClientID)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.ClientID), url.QueryEscape(p.ClientSecret))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token exchange: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("token exchange: %w", err)
+	}
+
+	var tr tokenResponse
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return nil, fmt.Errorf("token exchange: unexpected response (status %s)", resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK || tr.Error != "" {
+		return nil, fmt.Errorf("token exchange: %s %s", tr.Error, tr.ErrorDescription)
+	}
+	if tr.IDToken == "" {
+		return nil, errors.New("token exchange: response has no id_token")
+	}
+
+	return p.VerifyIDToken(ctx, tr.IDToken, nonce)
+}
+
+func getJSON(ctx context.Context, rawURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %s", rawURL, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
@@ -0,0 +1,326 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/config"
+)
+
+const (
+	testClientID = "seafood-api"
+	testKeyID    = "key-1"
+	testNonce    = "nonce-123"
+	testCode     = "code-abc"
+	testVerifier = "verifier-xyz"
+)
+
+// fakeIdP serves discovery, JWKS and a token endpoint that hands back idToken
+type fakeIdP struct {
+	*httptest.Server
+	t       *testing.T
+	key     *rsa.PrivateKey
+	idToken string
+}
+
+func newFakeIdP(t *testing.T) *fakeIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idp := &fakeIdP{t: t, key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(discovery{
+			Issuer:                idp.URL,
+			AuthorizationEndpoint: idp.URL + "/authorize",
+			TokenEndpoint:         idp.URL + "/token",
+			JWKSURI:               idp.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		e := big.NewInt(int64(key.PublicKey.E)).Bytes()
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []jwk{{
+				Kty: "RSA",
+				Kid: testKeyID,
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(e),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("token request: %v", err)
+		}
+		if r.PostForm.Get("code") != testCode || r.PostForm.Get("code_verifier") != testVerifier {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": idp.idToken})
+	})
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+func (idp *fakeIdP) provider() *Provider {
+	return &Provider{OIDCProviderConfig: config.OIDCProviderConfig{
+		Name:     "test",
+		Issuer:   idp.URL,
+		ClientID: testClientID,
+	}}
+}
+
+// claims returns valid claims for the fake provider
+func (idp *fakeIdP) claims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":            idp.URL,
+		"aud":            testClientID,
+		"sub":            "user-42",
+		"email":          "fisher@example.com",
+		"email_verified": true,
+		"nonce":          testNonce,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+	}
+}
+
+func (idp *fakeIdP) sign(claims jwt.MapClaims, kid string) string {
+	idp.t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(idp.key)
+	if err != nil {
+		idp.t.Fatal(err)
+	}
+	return signed
+}
+
+func TestExchange(t *testing.T) {
+	idp := newFakeIdP(t)
+
+	tests := []struct {
+		name    string
+		token   func() string
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			token: func() string { return idp.sign(idp.claims(), testKeyID) },
+		},
+		{
+			name: "wrong nonce",
+			token: func() string {
+				c := idp.claims()
+				c["nonce"] = "replayed"
+				return idp.sign(c, testKeyID)
+			},
+			wantErr: "nonce mismatch",
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				c := idp.claims()
+				c["aud"] = "someone-else"
+				return idp.sign(c, testKeyID)
+			},
+			wantErr: "audience",
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				c := idp.claims()
+				c["iss"] = "https://evil.example.com"
+				return idp.sign(c, testKeyID)
+			},
+			wantErr: "issuer",
+		},
+		{
+			name: "expired",
+			token: func() string {
+				c := idp.claims()
+				c["exp"] = time.Now().Add(-time.Hour).Unix()
+				return idp.sign(c, testKeyID)
+			},
+			wantErr: "expired",
+		},
+		{
+			name: "missing subject",
+			token: func() string {
+				c := idp.claims()
+				delete(c, "sub")
+				return idp.sign(c, testKeyID)
+			},
+			wantErr: "missing sub",
+		},
+		{
+			name: "HS256 signed with the public key",
+			token: func() string {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, idp.claims())
+				token.Header["kid"] = testKeyID
+				signed, err := token.SignedString(idp.key.PublicKey.N.Bytes())
+				if err != nil {
+					t.Fatal(err)
+				}
+				return signed
+			},
+			wantErr: "signing method",
+		},
+		{
+			name: "alg none",
+			token: func() string {
+				token := jwt.NewWithClaims(jwt.SigningMethodNone, idp.claims())
+				signed, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return signed
+			},
+			wantErr: "signing method",
+		},
+		{
+			name:    "unknown key id",
+			token:   func() string { return idp.sign(idp.claims(), "rotated-away") },
+			wantErr: "unknown signing key",
+		},
+		{
+			name: "signed by another key",
+			token: func() string {
+				other, err := rsa.GenerateKey(rand.Reader, 2048)
+				if err != nil {
+					t.Fatal(err)
+				}
+				token := jwt.NewWithClaims(jwt.SigningMethodRS256, idp.claims())
+				token.Header["kid"] = testKeyID
+				signed, err := token.SignedString(other)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return signed
+			},
+			wantErr: "signature is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp.idToken = tt.token()
+			claims, err := idp.provider().Exchange(context.Background(), testCode, testVerifier, testNonce)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Exchange: %v", err)
+				}
+				if claims.Subject != "user-42" || claims.Email != "fisher@example.com" || !bool(claims.EmailVerified) {
+					t.Errorf("unexpected claims %+v", claims)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Exchange succeeded, want error containing %q", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExchangeRejectedCode(t *testing.T) {
+	idp := newFakeIdP(t)
+	idp.idToken = idp.sign(idp.claims(), testKeyID)
+
+	_, err := idp.provider().Exchange(context.Background(), testCode, "wrong-verifier", testNonce)
+	if err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Fatalf("error = %v, want invalid_grant", err)
+	}
+}
+
+func TestVerifyIDTokenEmailVerified(t *testing.T) {
+	idp := newFakeIdP(t)
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  bool
+	}{
+		{"bool true", true, true},
+		{"bool false", false, false},
+		{"string true", "true", true},
+		{"string false", "false", false},
+		{"missing", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := idp.claims()
+			if tt.value == nil {
+				delete(c, "email_verified")
+			} else {
+				c["email_verified"] = tt.value
+			}
+
+			claims, err := idp.provider().VerifyIDToken(context.Background(), idp.sign(c, testKeyID), testNonce)
+			if err != nil {
+				t.Fatalf("VerifyIDToken: %v", err)
+			}
+			if bool(claims.EmailVerified) != tt.want {
+				t.Errorf("EmailVerified = %v, want %v", claims.EmailVerified, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	idp := newFakeIdP(t)
+
+	authURL, err := idp.provider().AuthCodeURL(context.Background(), "state-1", testNonce, testVerifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		idp.URL + "/authorize?",
+		"state=state-1",
+		"nonce=" + testNonce,
+		"code_challenge=" + CodeChallenge(testVerifier),
+		"code_challenge_method=S256",
+	} {
+		if !strings.Contains(authURL, want) {
+			t.Errorf("authorization URL %q is missing %q", authURL, want)
+		}
+	}
+}
+
+func TestDiscoveryIssuerMismatch(t *testing.T) {
+	idp := newFakeIdP(t)
+	p := idp.provider()
+	p.Issuer = idp.URL + "/other"
+
+	if _, err := p.AuthCodeURL(context.Background(), "s", "n", "v"); err == nil {
+		t.Fatal("AuthCodeURL succeeded with a mismatched issuer")
+	}
+}
+
+// CodeChallenge must match the RFC 7636 Appendix B example
+func TestCodeChallenge(t *testing.T) {
+	got := CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if want := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"; got != want {
+		t.Errorf("CodeChallenge = %s, want %s", got, want)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/manjunath-tintbytes/seafoodai.api/internal/handlers"
 	"github.com/manjunath-tintbytes/seafoodai.api/internal/middleware"
+	"github.com/manjunath-tintbytes/seafoodai.api/internal/models"
 	"gorm.io/gorm"
 )
 
@@ -11,17 +12,153 @@ func RegisterRoutes(r *gin.Engine, db *gorm.DB) {
 	r.GET("/", handlers.WelcomeHandler(db))
 	r.POST("/signup", handlers.Signup)
 	r.POST("/login", handlers.Login)
+	r.POST("/login/2fa", handlers.LoginTwoFactor)
 	r.POST("/forgot-password", handlers.ForgotPassword)
 	r.POST("/reset-password", handlers.ResetPassword)
+	r.POST("/unlock-account", handlers.UnlockAccount)
+	r.POST("/token/refresh", handlers.RefreshToken)
+	r.GET("/auth/oidc/:provider/login", handlers.OIDCLogin)
+	r.POST("/auth/oidc/:provider/callback", handlers.OIDCCallback)
+	r.POST("/verify-email", handlers.VerifyEmail)
+	r.POST("/resend-verification", handlers.ResendVerification)
 
 	// Protected routes
 	protected := r.Group("")
 	protected.Use(middleware.AuthMiddleware())
 	{
 		protected.GET("/profile", handlers.GetProfile)
-		protected.GET("/market-prices", handlers.GetMarketPricesOptimized(db))
-		protected.GET("/landings", handlers.GetLandings(db))
-		protected.GET("/market-signals", handlers.GetMarketSignals(db))
-		protected.GET("/quotas", handlers.GetQuotas(db))
 	}
+
+	// Account routes that need a signed-in user rather than an API key
+	account := protected.Group("")
+	account.Use(middleware.RequireInteractive())
+	{
+		account.PATCH("/profile", handlers.UpdateProfile)
+		account.POST("/profile/change-password", handlers.ChangePassword)
+		account.DELETE("/profile", handlers.DeleteProfile)
+		account.POST("/logout", handlers.Logout)
+		account.POST("/logout-all", handlers.LogoutAll)
+		account.GET("/sessions", handlers.GetSessions)
+		account.DELETE("/sessions/:id", handlers.RevokeSession)
+		account.POST("/api-keys", handlers.CreateAPIKey)
+		account.GET("/api-keys", handlers.ListAPIKeys)
+		account.DELETE("/api-keys/:id", handlers.RevokeAPIKey)
+		account.POST("/organizations", handlers.CreateOrganization)
+		account.GET("/organizations", handlers.ListOrganizations)
+		account.POST("/organizations/invites/accept", handlers.AcceptOrganizationInvite)
+		account.GET("/organizations/:id", handlers.GetOrganization)
+		account.PATCH("/organizations/:id", handlers.UpdateOrganization)
+		account.DELETE("/organizations/:id", handlers.DeleteOrganization)
+		account.PUT("/organizations/:id/members/:userId", handlers.UpdateOrganizationMember)
+		account.DELETE("/organizations/:id/members/:userId", handlers.RemoveOrganizationMember)
+		account.POST("/organizations/:id/invites", handlers.CreateOrganizationInvite)
+		account.GET("/organizations/:id/invites", handlers.ListOrganizationInvites)
+		account.DELETE("/organizations/:id/invites/:inviteId", handlers.RevokeOrganizationInvite)
+		account.POST("/2fa/setup", handlers.SetupTwoFactor)
+		account.POST("/2fa/confirm", handlers.ConfirmTwoFactor)
+		account.POST("/2fa/disable", handlers.DisableTwoFactor)
+		account.POST("/2fa/recovery-codes", handlers.RegenerateRecoveryCodes)
+	}
+
+	prices := middleware.RequireScope(models.ScopeMarketPrices)
+	landings := middleware.RequireScope(models.ScopeLandings)
+	signals := middleware.RequireScope(models.ScopeMarketSignals)
+	quotas := middleware.RequireScope(models.ScopeQuotas)
+	species := middleware.RequireScope(models.ScopeSpecies)
+
+	// Data routes (viewer and above)
+	viewer := protected.Group("")
+	viewer.Use(middleware.RequireVerifiedEmail(), middleware.RequireRole(models.RoleViewer))
+	{
+		viewer.GET("/market-prices", prices, handlers.GetMarketPricesOptimized(db))
+		viewer.GET("/prices/:id/revisions", prices, handlers.GetPriceRevisions(db))
+		viewer.GET("/landings", landings, handlers.GetLandings(db))
+		viewer.GET("/market-signals", signals, handlers.GetMarketSignals(db))
+		viewer.GET("/market-signals/:id", signals, handlers.GetMarketSignal(db))
+		viewer.GET("/species/:id/market-signals", signals, handlers.GetSpeciesMarketSignals(db))
+		viewer.GET("/quotas", quotas, handlers.GetQuotas(db))
+		viewer.GET("/search/species", species, handlers.SearchSpecies(db))
+	}
+
+	// Analytics routes (analyst and above)
+	analyst := protected.Group("")
+	analyst.Use(middleware.RequireVerifiedEmail(), middleware.RequireRole(models.RoleAnalyst))
+	{
+		analyst.GET("/market-prices/history", prices, handlers.GetMarketPriceHistory(db))
+		analyst.GET("/landings/summary", landings, handlers.GetLandingsSummary(db))
+		analyst.GET("/landings/trend", landings, handlers.GetLandingsTrend(db))
+		analyst.GET("/quotas/:product/history", quotas, handlers.GetQuotaHistory(db))
+	}
+
+	// Admin routes
+	adminOnly := []gin.HandlerFunc{
+		middleware.RequireVerifiedEmail(),
+		middleware.RequireRole(models.RoleAdmin),
+		middleware.RequireScope(models.ScopeAdmin),
+	}
+	admin := protected.Group("/admin")
+	admin.Use(adminOnly...)
+	{
+		admin.GET("/users", handlers.AdminListUsers)
+		admin.PUT("/users/:id/role", handlers.AdminUpdateUserRole)
+
+		// Catalog management
+		admin.GET("/species", handlers.AdminListSpecies(db))
+		admin.POST("/species", handlers.AdminCreateSpecies(db))
+		admin.GET("/species/:id", handlers.AdminGetSpecies(db))
+		admin.PATCH("/species/:id", handlers.AdminUpdateSpecies(db))
+		admin.DELETE("/species/:id", handlers.AdminDeleteSpecies(db))
+		admin.POST("/species/:id/restore", handlers.AdminRestoreSpecies(db))
+
+		admin.GET("/categories", handlers.AdminListCategories(db))
+		admin.POST("/categories", handlers.AdminCreateCategory(db))
+		admin.GET("/categories/:id", handlers.AdminGetCategory(db))
+		admin.PATCH("/categories/:id", handlers.AdminUpdateCategory(db))
+		admin.DELETE("/categories/:id", handlers.AdminDeleteCategory(db))
+		admin.POST("/categories/:id/restore", handlers.AdminRestoreCategory(db))
+
+		admin.GET("/regions", handlers.AdminListRegions(db))
+		admin.POST("/regions", handlers.AdminCreateRegion(db))
+		admin.GET("/regions/:id", handlers.AdminGetRegion(db))
+		admin.PATCH("/regions/:id", handlers.AdminUpdateRegion(db))
+		admin.DELETE("/regions/:id", handlers.AdminDeleteRegion(db))
+		admin.POST("/regions/:id/restore", handlers.AdminRestoreRegion(db))
+
+		admin.GET("/sub-regions", handlers.AdminListSubRegions(db))
+		admin.POST("/sub-regions", handlers.AdminCreateSubRegion(db))
+		admin.GET("/sub-regions/:id", handlers.AdminGetSubRegion(db))
+		admin.PATCH("/sub-regions/:id", handlers.AdminUpdateSubRegion(db))
+		admin.DELETE("/sub-regions/:id", handlers.AdminDeleteSubRegion(db))
+		admin.POST("/sub-regions/:id/restore", handlers.AdminRestoreSubRegion(db))
+
+		admin.GET("/seafood", handlers.AdminListSeafood(db))
+		admin.POST("/seafood", handlers.AdminCreateSeafood(db))
+		admin.GET("/seafood/:id", handlers.AdminGetSeafood(db))
+		admin.PATCH("/seafood/:id", handlers.AdminUpdateSeafood(db))
+		admin.DELETE("/seafood/:id", handlers.AdminDeleteSeafood(db))
+		admin.POST("/seafood/:id/restore", handlers.AdminRestoreSeafood(db))
+
+		// Species crosswalk
+		admin.GET("/canonical-species", handlers.AdminListCanonicalSpecies(db))
+		admin.POST("/canonical-species", handlers.AdminCreateCanonicalSpecies(db))
+		admin.GET("/canonical-species/:id", handlers.AdminGetCanonicalSpecies(db))
+		admin.PATCH("/canonical-species/:id", handlers.AdminUpdateCanonicalSpecies(db))
+		admin.DELETE("/canonical-species/:id", handlers.AdminDeleteCanonicalSpecies(db))
+		admin.POST("/canonical-species/:id/restore", handlers.AdminRestoreCanonicalSpecies(db))
+		admin.GET("/species-crosswalk", handlers.AdminListCrosswalk(db))
+		admin.GET("/species-crosswalk/unmapped", handlers.AdminListUnmappedSpecies(db))
+		admin.POST("/species-crosswalk", handlers.AdminCreateCrosswalk(db))
+		admin.DELETE("/species-crosswalk/:id", handlers.AdminDeleteCrosswalk(db))
+		admin.GET("/species-synonyms", handlers.AdminListSpeciesSynonyms(db))
+		admin.POST("/species-synonyms", handlers.AdminCreateSpeciesSynonym(db))
+		admin.DELETE("/species-synonyms/:id", handlers.AdminDeleteSpeciesSynonym(db))
+
+		// Price entry and correction
+		admin.POST("/prices", handlers.AdminCreatePrice(db))
+		admin.POST("/prices/bulk", handlers.AdminBulkCreatePrices(db))
+		admin.PATCH("/prices/:id", handlers.AdminCorrectPrice(db))
+	}
+
+	// Security audit log (admin only)
+	protected.GET("/audit-log", append(adminOnly, handlers.AdminListAuditLog)...)
 }
@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// NormalizeCurrency upper-cases a currency code and reports whether it is a valid ISO 4217 style code
+func NormalizeCurrency(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return code, currencyCodePattern.MatchString(code)
+}
+
+// CrossCurrency is the currency a pair is chained through when neither it nor
+// its inverse is quoted
+const CrossCurrency = "EUR"
+
+type ratePoint struct {
+	Date time.Time
+	Rate float64
+}
+
+// RateTable holds dated exchange rates keyed by currency pair
+type RateTable struct {
+	rates map[string][]ratePoint
+}
+
+func NewRateTable() *RateTable {
+	return &RateTable{rates: make(map[string][]ratePoint)}
+}
+
+func pairKey(base, quote string) string {
+	return base + "/" + quote
+}
+
+// Add records that 1 base = rate quote on the given date, keeping each pair sorted by date
+func (t *RateTable) Add(base, quote string, date time.Time, rate float64) {
+	if rate <= 0 {
+		return
+	}
+	key := pairKey(base, quote)
+	points := t.rates[key]
+	i := sort.Search(len(points), func(i int) bool { return !points[i].Date.Before(date) })
+	if i < len(points) && points[i].Date.Equal(date) {
+		points[i].Rate = rate
+		return
+	}
+	points = append(points, ratePoint{})
+	copy(points[i+1:], points[i:])
+	points[i] = ratePoint{Date: date, Rate: rate}
+	t.rates[key] = points
+}
+
+// latest returns the most recent rate for a pair on or before the given date
+func (t *RateTable) latest(base, quote string, on time.Time) (ratePoint, bool) {
+	points := t.rates[pairKey(base, quote)]
+	i := sort.Search(len(points), func(i int) bool { return points[i].Date.After(on) })
+	if i == 0 {
+		return ratePoint{}, false
+	}
+	return points[i-1], true
+}
+
+// Rate returns the from->to rate in effect on the given date and the date the rate was published.
+// Direct quotes are preferred, then the inverse of the opposite pair, then a cross rate
+// through CrossCurrency dated by the older of its two legs.
+func (t *RateTable) Rate(from, to string, on time.Time) (float64, time.Time, bool) {
+	if from == to {
+		return 1, time.Time{}, true
+	}
+	if p, ok := t.latest(from, to, on); ok {
+		return p.Rate, p.Date, true
+	}
+	if p, ok := t.latest(to, from, on); ok {
+		return 1 / p.Rate, p.Date, true
+	}
+	if from == CrossCurrency || to == CrossCurrency {
+		return 0, time.Time{}, false
+	}
+	toCross, toCrossDate, ok := t.Rate(from, CrossCurrency, on)
+	if !ok {
+		return 0, time.Time{}, false
+	}
+	fromCross, fromCrossDate, ok := t.Rate(CrossCurrency, to, on)
+	if !ok {
+		return 0, time.Time{}, false
+	}
+	rateDate := toCrossDate
+	if fromCrossDate.Before(rateDate) {
+		rateDate = fromCrossDate
+	}
+	return toCross * fromCross, rateDate, true
+}
+
+// Convert converts an amount between currencies using the rate in effect on the given date
+func (t *RateTable) Convert(amount float64, from, to string, on time.Time) (float64, time.Time, bool) {
+	rate, rateDate, ok := t.Rate(from, to, on)
+	if !ok {
+		return 0, time.Time{}, false
+	}
+	return amount * rate, rateDate, true
+}
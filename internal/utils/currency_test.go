@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestRateTableConvert(t *testing.T) {
+	rates := NewRateTable()
+	rates.Add("EUR", "USD", day("2025-01-01"), 1.10)
+	rates.Add("EUR", "USD", day("2025-01-10"), 1.20)
+	rates.Add("NOK", "EUR", day("2025-01-05"), 0.08)
+	rates.Add("GBP", "EUR", day("2025-01-03"), 1.25)
+	rates.Add("EUR", "JPY", day("2025-01-08"), 160)
+	rates.Add("EUR", "CHF", day("2025-01-01"), -1)
+
+	tests := []struct {
+		name     string
+		amount   float64
+		from, to string
+		on       string
+		want     float64
+		rateDate string
+		ok       bool
+	}{
+		{"same currency", 10, "EUR", "EUR", "2025-01-01", 10, "", true},
+		{"direct pair", 10, "EUR", "USD", "2025-01-05", 11, "2025-01-01", true},
+		{"direct pair on the publication date", 10, "EUR", "USD", "2025-01-10", 12, "2025-01-10", true},
+		{"direct pair uses the latest earlier rate", 10, "EUR", "USD", "2025-03-01", 12, "2025-01-10", true},
+		{"inverse pair", 11, "USD", "EUR", "2025-01-05", 10, "2025-01-01", true},
+		{"cross through EUR", 100, "NOK", "USD", "2025-01-12", 9.6, "2025-01-05", true},
+		{"cross through EUR, both legs inverted", 160, "JPY", "GBP", "2025-01-09", 0.8, "2025-01-03", true},
+		{"cross dated by the older leg", 10, "GBP", "USD", "2025-01-11", 15, "2025-01-03", true},
+		{"before the first rate", 10, "EUR", "USD", "2024-12-31", 0, "", false},
+		{"cross leg missing on the date", 10, "NOK", "USD", "2025-01-04", 0, "", false},
+		{"unknown pair", 10, "EUR", "AUD", "2025-01-05", 0, "", false},
+		{"non-positive rates are ignored", 10, "EUR", "CHF", "2025-01-05", 0, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, rateDate, ok := rates.Convert(tt.amount, tt.from, tt.to, day(tt.on))
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Convert = %v, want %v", got, tt.want)
+			}
+			var wantDate time.Time
+			if tt.rateDate != "" {
+				wantDate = day(tt.rateDate)
+			}
+			if !rateDate.Equal(wantDate) {
+				t.Errorf("rate date = %v, want %v", rateDate, wantDate)
+			}
+		})
+	}
+}
+
+func TestRateTableAddReplacesSameDay(t *testing.T) {
+	rates := NewRateTable()
+	rates.Add("EUR", "USD", day("2025-01-10"), 1.20)
+	rates.Add("EUR", "USD", day("2025-01-01"), 1.10)
+	rates.Add("EUR", "USD", day("2025-01-10"), 1.15)
+
+	if rate, _, _ := rates.Rate("EUR", "USD", day("2025-01-05")); rate != 1.10 {
+		t.Errorf("rate before the update = %v, want 1.10", rate)
+	}
+	if rate, _, _ := rates.Rate("EUR", "USD", day("2025-01-10")); rate != 1.15 {
+		t.Errorf("rate after the update = %v, want 1.15", rate)
+	}
+}
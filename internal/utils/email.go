@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/smtp"
 	"os"
+	"strings"
+	"time"
 )
 
 type EmailConfig struct {
@@ -24,15 +26,38 @@ func GetEmailConfig() *EmailConfig {
 	}
 }
 
-func SendPasswordResetEmail(to, token string) error {
-	cfg := GetEmailConfig()
-
+func getFrontendURL() string {
 	frontendURL := os.Getenv("FRONTEND_URL")
 	if frontendURL == "" {
 		frontendURL = "http://localhost:3000"
 	}
+	return frontendURL
+}
+
+// headerValue drops CR and LF so a value cannot start a new mail header
+func headerValue(v string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(v)
+}
+
+// sendEmail sends a plain-text email through the configured SMTP server
+func sendEmail(to, subject, body string) error {
+	cfg := GetEmailConfig()
+	to, subject = headerValue(to), headerValue(subject)
+
+	message := fmt.Sprintf("From: %s\r\n"+
+		"To: %s\r\n"+
+		"Subject: %s\r\n"+
+		"\r\n"+
+		"%s\r\n", cfg.From, to, subject, body)
+
+	auth := smtp.PlainAuth("", cfg.SMTPUser, cfg.SMTPPass, cfg.SMTPHost)
 
-	resetLink := fmt.Sprintf("%s/reset-password?token=%s", frontendURL, token)
+	addr := fmt.Sprintf("%s:%s", cfg.SMTPHost, cfg.SMTPPort)
+	return smtp.SendMail(addr, auth, cfg.From, []string{to}, []byte(message))
+}
+
+func SendPasswordResetEmail(to, token string) error {
+	resetLink := fmt.Sprintf("%s/reset-password?token=%s", getFrontendURL(), token)
 
 	subject := "Password Reset Request"
 	body := fmt.Sprintf(`
@@ -50,17 +75,93 @@ Best regards,
 Seafood AI Team
 `, resetLink)
 
-	message := fmt.Sprintf("From: %s\r\n"+
-		"To: %s\r\n"+
-		"Subject: %s\r\n"+
-		"\r\n"+
-		"%s\r\n", cfg.From, to, subject, body)
+	return sendEmail(to, subject, body)
+}
 
-	auth := smtp.PlainAuth("", cfg.SMTPUser, cfg.SMTPPass, cfg.SMTPHost)
+func SendVerificationEmail(to, token string) error {
+	verifyLink := fmt.Sprintf("%s/verify-email?token=%s", getFrontendURL(), token)
 
-	addr := fmt.Sprintf("%s:%s", cfg.SMTPHost, cfg.SMTPPort)
-	err := smtp.SendMail(addr, auth, cfg.From, []string{to}, []byte(message))
-	println([]string{to})
+	subject := "Verify your email address"
+	body := fmt.Sprintf(`
+Hello,
+
+Thanks for signing up for Seafood AI. Please confirm your email address by clicking the link below:
+
+%s
+
+This link will expire in 24 hours.
+
+If you didn't create an account, please ignore this email.
+
+Best regards,
+Seafood AI Team
+`, verifyLink)
+
+	return sendEmail(to, subject, body)
+}
+
+func SendEmailChangeVerificationEmail(to, token string) error {
+	verifyLink := fmt.Sprintf("%s/verify-email?token=%s", getFrontendURL(), token)
+
+	subject := "Confirm your new email address"
+	body := fmt.Sprintf(`
+Hello,
+
+We received a request to change the email address on your Seafood AI account to this one. Please confirm it by clicking the link below:
+
+%s
+
+This link will expire in 24 hours. Until then your account keeps using its current email address.
+
+If you didn't request this change, please ignore this email.
+
+Best regards,
+Seafood AI Team
+`, verifyLink)
+
+	return sendEmail(to, subject, body)
+}
+
+func SendAccountUnlockEmail(to, token string, lockedUntil time.Time) error {
+	unlockLink := fmt.Sprintf("%s/unlock-account?token=%s", getFrontendURL(), token)
+
+	subject := "Your account has been locked"
+	body := fmt.Sprintf(`
+Hello,
+
+We locked your account after several failed sign-in attempts. It will unlock automatically at %s.
+
+If this was you, you can unlock it now by clicking the link below:
+
+%s
+
+If this wasn't you, we recommend resetting your password.
+
+Best regards,
+Seafood AI Team
+`, lockedUntil.UTC().Format("15:04 MST on January 2, 2006"), unlockLink)
+
+	return sendEmail(to, subject, body)
+}
+
+func SendOrganizationInviteEmail(to, orgName, inviterName, token string) error {
+	inviteLink := fmt.Sprintf("%s/accept-invite?token=%s", getFrontendURL(), token)
+
+	subject := fmt.Sprintf("You've been invited to join %s on Seafood AI", orgName)
+	body := fmt.Sprintf(`
+Hello,
+
+%s has invited you to join the %s organization on Seafood AI. Click the link below to accept the invitation:
+
+%s
+
+This link will expire in 7 days. You will need to sign in, or sign up, with this email address.
+
+If you weren't expecting this invitation, please ignore this email.
+
+Best regards,
+Seafood AI Team
+`, inviterName, orgName, inviteLink)
 
-	return err
+	return sendEmail(to, subject, body)
 }
@@ -1,5 +1,10 @@
 package utils
 
+import (
+	"math"
+	"time"
+)
+
 func CalculateChange(latest float64, past *float64) *float64 {
 	if past != nil && *past != 0 {
 		change := ((latest - *past) / *past) * 100
@@ -7,3 +12,64 @@ func CalculateChange(latest float64, past *float64) *float64 {
 	}
 	return nil
 }
+
+// CalculateCAGR returns the compound annual growth rate in percent between two values `years` apart
+func CalculateCAGR(latest float64, past *float64, years int) *float64 {
+	if past != nil && *past > 0 && latest >= 0 && years > 0 {
+		cagr := (math.Pow(latest / *past, 1/float64(years)) - 1) * 100
+		return &cagr
+	}
+	return nil
+}
+
+// LinearSlope returns the least-squares slope of ys against xs
+func LinearSlope(xs, ys []float64) (float64, bool) {
+	n := float64(len(xs))
+	if len(xs) < 2 || len(xs) != len(ys) {
+		return 0, false
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+		sumXY += xs[i] * ys[i]
+		sumXX += xs[i] * xs[i]
+	}
+
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0, false
+	}
+	return (n*sumXY - sumX*sumY) / denom, true
+}
+
+// MaxExhaustionHorizonDays caps quota exhaustion projections; a burn rate
+// slower than this says nothing useful about when the quota runs out
+const MaxExhaustionHorizonDays = 3650
+
+// ProjectExhaustion returns how many days `remaining` lasts at `dailyBurn` per
+// day and the date it runs out, counted from `latest`. It reports false when
+// the quota is not shrinking or would last beyond MaxExhaustionHorizonDays.
+func ProjectExhaustion(latest time.Time, remaining, dailyBurn float64) (float64, time.Time, bool) {
+	if !(dailyBurn > 0) || math.IsInf(dailyBurn, 0) || remaining < 0 {
+		return 0, time.Time{}, false
+	}
+	days := remaining / dailyBurn
+	if days > MaxExhaustionHorizonDays {
+		return 0, time.Time{}, false
+	}
+	return days, latest.AddDate(0, 0, int(math.Ceil(days))), true
+}
+
+// LastResetIndex returns the index of the first reading after the last
+// increase in ys, such as a yearly quota reset. Readings before it belong to
+// an earlier period and should not be fitted together with the current one.
+func LastResetIndex(ys []float64) int {
+	for i := len(ys) - 1; i > 0; i-- {
+		if ys[i] > ys[i-1] {
+			return i
+		}
+	}
+	return 0
+}
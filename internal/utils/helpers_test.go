@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLinearSlope(t *testing.T) {
+	tests := []struct {
+		name  string
+		xs    []float64
+		ys    []float64
+		slope float64
+		ok    bool
+	}{
+		{"exact line", []float64{0, 1, 2, 3}, []float64{100, 90, 80, 70}, -10, true},
+		{"flat", []float64{0, 1, 2}, []float64{5, 5, 5}, 0, true},
+		{"noisy decline", []float64{0, 1, 2, 3}, []float64{10, 9, 7, 6}, -1.4, true},
+		{"one point", []float64{0}, []float64{10}, 0, false},
+		{"same x", []float64{2, 2, 2}, []float64{1, 2, 3}, 0, false},
+		{"length mismatch", []float64{0, 1}, []float64{1}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slope, ok := LinearSlope(tt.xs, tt.ys)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && math.Abs(slope-tt.slope) > 1e-9 {
+				t.Errorf("slope = %v, want %v", slope, tt.slope)
+			}
+		})
+	}
+}
+
+func TestProjectExhaustion(t *testing.T) {
+	latest := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		remaining float64
+		burn      float64
+		days      float64
+		date      string
+		ok        bool
+	}{
+		{"whole days", 50, 5, 10, "2025-10-11", true},
+		{"partial day rounds up", 10, 4, 2.5, "2025-10-04", true},
+		{"already exhausted", 0, 2, 0, "2025-10-01", true},
+		{"not shrinking", 50, 0, 0, "", false},
+		{"growing", 50, -1, 0, "", false},
+		{"beyond horizon", 100, 0.0001, 0, "", false},
+		{"near-zero burn does not overflow", 100, 1e-12, 0, "", false},
+		{"NaN burn", 100, math.NaN(), 0, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days, date, ok := ProjectExhaustion(latest, tt.remaining, tt.burn)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if math.Abs(days-tt.days) > 1e-9 {
+				t.Errorf("days = %v, want %v", days, tt.days)
+			}
+			if got := date.Format("2006-01-02"); got != tt.date {
+				t.Errorf("date = %s, want %s", got, tt.date)
+			}
+		})
+	}
+}
+
+func TestLastResetIndex(t *testing.T) {
+	tests := []struct {
+		name string
+		ys   []float64
+		want int
+	}{
+		{"empty", nil, 0},
+		{"only shrinking", []float64{100, 80, 80, 60}, 0},
+		{"yearly reset", []float64{30, 20, 10, 100, 95, 90}, 3},
+		{"two resets", []float64{10, 100, 50, 100, 90}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LastResetIndex(tt.ys); got != tt.want {
+				t.Errorf("LastResetIndex = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBurnRateAcrossReset(t *testing.T) {
+	// Ten days of 5/day decline, a refill, then three days of 2/day decline
+	var xs, ys []float64
+	for i := 0; i < 10; i++ {
+		xs = append(xs, float64(i))
+		ys = append(ys, 100-5*float64(i))
+	}
+	for i := 0; i < 3; i++ {
+		xs = append(xs, float64(10+i))
+		ys = append(ys, 200-2*float64(i))
+	}
+
+	reset := LastResetIndex(ys)
+	slope, ok := LinearSlope(xs[reset:], ys[reset:])
+	if !ok || math.Abs(slope+2) > 1e-9 {
+		t.Errorf("slope after reset = %v (ok %v), want -2", slope, ok)
+	}
+}
@@ -9,24 +9,50 @@ import (
 )
 
 type Claims struct {
-	UserID int    `json:"user_id"`
-	Email  string `json:"email"`
+	UserID    int    `json:"user_id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID int    `json:"sid"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID int, email string) (string, error) {
+// AccessTokenTTL is how long an access token is valid, set with ACCESS_TOKEN_TTL (default 15m)
+func AccessTokenTTL() time.Duration {
+	return durationFromEnv("ACCESS_TOKEN_TTL", 15*time.Minute)
+}
+
+// RefreshTokenTTL is how long an unused refresh token is valid, set with REFRESH_TOKEN_TTL (default 720h)
+func RefreshTokenTTL() time.Duration {
+	return durationFromEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+}
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return fallback
+}
+
+func GenerateToken(userID int, email, role string, sessionID int) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(AccessTokenTTL())
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	secret := os.Getenv("JWT_SECRET")
-	return token.SignedString([]byte(secret))
+	signed, err := token.SignedString([]byte(secret))
+	return signed, expiresAt, err
 }
 
 func ValidateToken(tokenString string) (*Claims, error) {
@@ -34,7 +60,56 @@ func ValidateToken(tokenString string) (*Claims, error) {
 
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		return []byte(secret), nil
-	})
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	// Challenge tokens carry an audience and are not access tokens
+	if len(claims.Audience) > 0 {
+		return nil, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
+
+// Audience of the short-lived token handed out between the password and 2FA steps of a login
+const challengeAudience = "login-2fa"
+
+// ChallengeTokenTTL is how long the user has to complete the second login step
+const ChallengeTokenTTL = 5 * time.Minute
+
+// GenerateChallengeToken issues the token that POST /login/2fa exchanges for a session
+func GenerateChallengeToken(userID int) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ChallengeTokenTTL)
+	claims := Claims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{challengeAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	secret := os.Getenv("JWT_SECRET")
+	signed, err := token.SignedString([]byte(secret))
+	return signed, expiresAt, err
+}
+
+func ValidateChallengeToken(tokenString string) (*Claims, error) {
+	secret := os.Getenv("JWT_SECRET")
+
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(challengeAudience))
 
 	if err != nil {
 		return nil, err
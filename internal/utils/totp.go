@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// TOTP parameters (RFC 6238 defaults, which authenticator apps assume)
+const (
+	TOTPDigits = 6
+	TOTPPeriod = 30 * time.Second
+
+	// totpSkew is how many periods either side of now a code is accepted for
+	totpSkew = 1
+)
+
+// TOTPIssuer is the account issuer shown in authenticator apps, set with TOTP_ISSUER (default "Seafood AI")
+func TOTPIssuer() string {
+	if v := os.Getenv("TOTP_ISSUER"); v != "" {
+		return v
+	}
+	return "Seafood AI"
+}
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns a new random 160-bit secret, base32 encoded
+func GenerateTOTPSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(b), nil
+}
+
+// TOTPURI builds the otpauth:// URI that authenticator apps read from a QR code
+func TOTPURI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer + ":" + account)
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(TOTPDigits))
+	params.Set("period", fmt.Sprint(int(TOTPPeriod/time.Second)))
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// TOTPStep returns the RFC 6238 time step containing t
+func TOTPStep(t time.Time) int64 {
+	return t.Unix() / int64(TOTPPeriod/time.Second)
+}
+
+// TOTPCode returns the code for the base32 secret at time t
+func TOTPCode(secret string, t time.Time) (string, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return HOTP(key, uint64(TOTPStep(t)), TOTPDigits), nil
+}
+
+// ValidateTOTP checks code against the steps around t and returns the matching
+// step. Steps at or before lastStep are refused so a code cannot be replayed.
+func ValidateTOTP(secret, code string, t time.Time, lastStep int64) (int64, bool) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil || len(code) != TOTPDigits {
+		return 0, false
+	}
+
+	now := TOTPStep(t)
+	for step := now - totpSkew; step <= now+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		if hmac.Equal([]byte(HOTP(key, uint64(step), TOTPDigits)), []byte(code)) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// HOTP computes an RFC 4226 one-time password with HMAC-SHA1
+func HOTP(key []byte, counter uint64, digits int) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
+
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	s := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(secret), " ", ""))
+	return totpEncoding.DecodeString(strings.TrimRight(s, "="))
+}
@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// RFC 6238 Appendix B uses the ASCII key "12345678901234567890" for SHA-1
+var rfc6238Key = []byte("12345678901234567890")
+
+func TestHOTPRFC6238Vectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	for _, tt := range tests {
+		step := TOTPStep(time.Unix(tt.unix, 0))
+		if got := HOTP(rfc6238Key, uint64(step), 8); got != tt.code {
+			t.Errorf("HOTP at T=%d = %s, want %s", tt.unix, got, tt.code)
+		}
+	}
+}
+
+func TestTOTPCodeMatchesHOTP(t *testing.T) {
+	secret := totpEncoding.EncodeToString(rfc6238Key)
+
+	// The six-digit code is the last six digits of the eight-digit vector
+	got, err := TOTPCode(secret, time.Unix(59, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "287082" {
+		t.Errorf("TOTPCode at T=59 = %s, want 287082", got)
+	}
+
+	// Lower case and spaces, as users type secrets in, decode the same
+	if _, err := TOTPCode("gezd gnbv gy3t qojq gezd gnbv gy3t qojq", time.Unix(59, 0)); err != nil {
+		t.Errorf("TOTPCode with formatted secret: %v", err)
+	}
+}
+
+func TestValidateTOTPWindow(t *testing.T) {
+	secret := totpEncoding.EncodeToString(rfc6238Key)
+	now := time.Unix(1111111111, 0)
+	nowStep := TOTPStep(now)
+
+	tests := []struct {
+		name   string
+		offset int64
+		ok     bool
+	}{
+		{"current step", 0, true},
+		{"one step behind", -1, true},
+		{"one step ahead", 1, true},
+		{"two steps behind", -2, false},
+		{"two steps ahead", 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := HOTP(rfc6238Key, uint64(nowStep+tt.offset), TOTPDigits)
+			step, ok := ValidateTOTP(secret, code, now, 0)
+			if ok != tt.ok {
+				t.Fatalf("ValidateTOTP ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && step != nowStep+tt.offset {
+				t.Errorf("ValidateTOTP step = %d, want %d", step, nowStep+tt.offset)
+			}
+		})
+	}
+}
+
+func TestValidateTOTPReplay(t *testing.T) {
+	secret := totpEncoding.EncodeToString(rfc6238Key)
+	now := time.Unix(1111111111, 0)
+	code := HOTP(rfc6238Key, uint64(TOTPStep(now)), TOTPDigits)
+
+	step, ok := ValidateTOTP(secret, code, now, 0)
+	if !ok {
+		t.Fatal("first use of code rejected")
+	}
+
+	// The step that was just used, and anything before it, is refused
+	if _, ok := ValidateTOTP(secret, code, now, step); ok {
+		t.Error("replayed code accepted")
+	}
+
+	// A code from an earlier step in the window is refused once a later step was used
+	earlier := HOTP(rfc6238Key, uint64(step-1), TOTPDigits)
+	if _, ok := ValidateTOTP(secret, earlier, now, step); ok {
+		t.Error("code older than the last used step accepted")
+	}
+
+	// The next step is still accepted
+	later := HOTP(rfc6238Key, uint64(step+1), TOTPDigits)
+	if _, ok := ValidateTOTP(secret, later, now, step); !ok {
+		t.Error("code newer than the last used step rejected")
+	}
+}
+
+func TestValidateTOTPMalformed(t *testing.T) {
+	secret := totpEncoding.EncodeToString(rfc6238Key)
+	now := time.Unix(59, 0)
+
+	for _, code := range []string{"", "28708", "2870820", "abcdef"} {
+		if _, ok := ValidateTOTP(secret, code, now, 0); ok {
+			t.Errorf("ValidateTOTP(%q) accepted", code)
+		}
+	}
+	if _, ok := ValidateTOTP("not base32!", "287082", now, 0); ok {
+		t.Error("ValidateTOTP accepted an undecodable secret")
+	}
+}
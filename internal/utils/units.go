@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+)
+
+type Unit string
+
+const (
+	UnitKilogram  Unit = "kg"
+	UnitPound     Unit = "lb"
+	UnitMetricTon Unit = "mt"
+	UnitPiece     Unit = "unit"
+)
+
+var (
+	ErrUnknownUnit        = errors.New("unknown unit of measure")
+	ErrUnknownPieceWeight = errors.New("piece weight unknown, cannot convert per-unit value")
+)
+
+// Kilograms in one of each mass unit
+var kilogramsPer = map[Unit]float64{
+	UnitKilogram:  1,
+	UnitPound:     0.45359237,
+	UnitMetricTon: 1000,
+}
+
+var unitAliases = map[string]Unit{
+	"kg":         UnitKilogram,
+	"kgs":        UnitKilogram,
+	"kilo":       UnitKilogram,
+	"kilogram":   UnitKilogram,
+	"kilograms":  UnitKilogram,
+	"lb":         UnitPound,
+	"lbs":        UnitPound,
+	"pound":      UnitPound,
+	"pounds":     UnitPound,
+	"mt":         UnitMetricTon,
+	"t":          UnitMetricTon,
+	"tonne":      UnitMetricTon,
+	"tonnes":     UnitMetricTon,
+	"metric_ton": UnitMetricTon,
+	"unit":       UnitPiece,
+	"units":      UnitPiece,
+	"piece":      UnitPiece,
+	"pieces":     UnitPiece,
+	"each":       UnitPiece,
+	"ea":         UnitPiece,
+}
+
+// ParseUnit maps a free-form unit string to a known unit
+func ParseUnit(s string) (Unit, bool) {
+	u, ok := unitAliases[strings.ToLower(strings.TrimSpace(s))]
+	return u, ok
+}
+
+// kilogramsIn returns the mass of one unit, using the piece weight for per-unit values
+func kilogramsIn(u Unit, pieceWeightKg *float64) (float64, error) {
+	if u == UnitPiece {
+		if pieceWeightKg == nil || *pieceWeightKg <= 0 {
+			return 0, ErrUnknownPieceWeight
+		}
+		return *pieceWeightKg, nil
+	}
+	kg, ok := kilogramsPer[u]
+	if !ok {
+		return 0, ErrUnknownUnit
+	}
+	return kg, nil
+}
+
+// ConvertQuantity converts an amount (e.g. landed volume) between units
+func ConvertQuantity(amount float64, from, to Unit, pieceWeightKg *float64) (float64, error) {
+	if from == to {
+		return amount, nil
+	}
+	fromKg, err := kilogramsIn(from, pieceWeightKg)
+	if err != nil {
+		return 0, err
+	}
+	toKg, err := kilogramsIn(to, pieceWeightKg)
+	if err != nil {
+		return 0, err
+	}
+	return amount * fromKg / toKg, nil
+}
+
+// ConvertPrice converts a price quoted per one unit into a price per another unit
+func ConvertPrice(price float64, from, to Unit, pieceWeightKg *float64) (float64, error) {
+	if from == to {
+		return price, nil
+	}
+	fromKg, err := kilogramsIn(from, pieceWeightKg)
+	if err != nil {
+		return 0, err
+	}
+	toKg, err := kilogramsIn(to, pieceWeightKg)
+	if err != nil {
+		return 0, err
+	}
+	return price * toKg / fromKg, nil
+}
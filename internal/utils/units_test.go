@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestConvertQuantity(t *testing.T) {
+	pieceKg := 0.5
+
+	tests := []struct {
+		name   string
+		amount float64
+		from   Unit
+		to     Unit
+		piece  *float64
+		want   float64
+		err    error
+	}{
+		{"same unit", 12, UnitPound, UnitPound, nil, 12, nil},
+		{"pounds to kilograms", 1000, UnitPound, UnitKilogram, nil, 453.59237, nil},
+		{"pounds to metric tons", 2204.62262185, UnitPound, UnitMetricTon, nil, 1, nil},
+		{"metric tons to pounds", 1, UnitMetricTon, UnitPound, nil, 2204.62262185, nil},
+		{"pieces to kilograms", 10, UnitPiece, UnitKilogram, &pieceKg, 5, nil},
+		{"kilograms to pieces", 5, UnitKilogram, UnitPiece, &pieceKg, 10, nil},
+		{"pieces without weight", 10, UnitPiece, UnitKilogram, nil, 0, ErrUnknownPieceWeight},
+		{"unknown unit", 1, Unit("crate"), UnitKilogram, nil, 0, ErrUnknownUnit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertQuantity(tt.amount, tt.from, tt.to, tt.piece)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("ConvertQuantity = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// A price per pound is cheaper per pound than per kilogram, the inverse of a quantity
+func TestConvertPrice(t *testing.T) {
+	got, err := ConvertPrice(10, UnitKilogram, UnitPound, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 4.5359237; math.Abs(got-want) > 1e-9 {
+		t.Errorf("ConvertPrice = %v, want %v", got, want)
+	}
+}
+
+func TestParseUnit(t *testing.T) {
+	for in, want := range map[string]Unit{" LBS ": UnitPound, "Tonne": UnitMetricTon, "each": UnitPiece} {
+		if got, ok := ParseUnit(in); !ok || got != want {
+			t.Errorf("ParseUnit(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+	if _, ok := ParseUnit("crate"); ok {
+		t.Error("ParseUnit accepted an unknown unit")
+	}
+}